@@ -0,0 +1,117 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+const baselineVersion = 1
+
+// baseline records the findings present when it was written, so that only
+// new findings are reported afterwards. Findings are identified by their
+// fingerprint rather than their position, so that a baseline survives
+// unrelated edits that move code around.
+type baseline struct {
+	Version int             `json:"version"`
+	Issues  []baselineIssue `json:"issues"`
+}
+
+// baselineIssue is the fingerprint of a finding, along with the number of
+// findings that share it.
+type baselineIssue struct {
+	Rule    string `json:"rule"`
+	Package string `json:"package"`
+	Message string `json:"message"`
+	Text    string `json:"text"` // The source text of the finding, such as the offending key.
+	Count   int    `json:"count"`
+}
+
+func fingerprint(f finding) baselineIssue {
+	return baselineIssue{
+		Rule:    f.Category,
+		Package: f.Package,
+		Message: strings.Join(strings.Fields(f.Message), " "),
+		Text:    f.Text,
+	}
+}
+
+func (i baselineIssue) key() string {
+	return strings.Join([]string{i.Rule, i.Package, i.Message, i.Text}, "\x00")
+}
+
+// newBaseline returns the baseline of findings.
+func newBaseline(findings []finding) *baseline {
+	b := &baseline{Version: baselineVersion, Issues: []baselineIssue{}}
+	index := make(map[string]int)
+	for _, f := range findings {
+		issue := fingerprint(f)
+		if i, ok := index[issue.key()]; ok {
+			b.Issues[i].Count++
+			continue
+		}
+		issue.Count = 1
+		index[issue.key()] = len(b.Issues)
+		b.Issues = append(b.Issues, issue)
+	}
+
+	slices.SortFunc(b.Issues, func(x, y baselineIssue) int {
+		return cmp.Or(
+			strings.Compare(x.Package, y.Package),
+			strings.Compare(x.Rule, y.Rule),
+			strings.Compare(x.Message, y.Message),
+			strings.Compare(x.Text, y.Text),
+		)
+	})
+	return b
+}
+
+// filter returns the findings that are not recorded in b. When more
+// findings share a fingerprint than were recorded, the extra ones are new.
+func (b *baseline) filter(findings []finding) []finding {
+	remaining := make(map[string]int)
+	for _, issue := range b.Issues {
+		remaining[issue.key()] += issue.Count
+	}
+
+	var filtered []finding
+	for _, f := range findings {
+		key := fingerprint(f).key()
+		if remaining[key] > 0 {
+			remaining[key]--
+			continue
+		}
+		filtered = append(filtered, f)
+	}
+	return filtered
+}
+
+func readBaseline(path string) (*baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("zaplint: %w", err)
+	}
+
+	var b baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("zaplint: %s: %w", path, err)
+	}
+	if b.Version != baselineVersion {
+		return nil, fmt.Errorf("zaplint: %s: unsupported baseline version %d", path, b.Version)
+	}
+	return &b, nil
+}
+
+func writeBaseline(path string, findings []finding) error {
+	data, err := json.MarshalIndent(newBaseline(findings), "", "  ")
+	if err != nil {
+		return fmt.Errorf("zaplint: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("zaplint: %w", err)
+	}
+	return nil
+}
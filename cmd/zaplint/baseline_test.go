@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testdata = "../../testdata/src"
+
+func TestBaseline(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "baseline.json")
+
+	var stdout, stderr bytes.Buffer
+	d := &driver{dir: testdata}
+	if code := d.run([]string{"-key-naming-convention", "snake", "-write-baseline", path, "./key_naming_convention/camel"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("unexpected output: %s", stdout.String())
+	}
+
+	b, err := readBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range b.Issues {
+		if issue.Rule != "key-naming-convention" || issue.Package != "src/key_naming_convention/camel" || issue.Text == "" || issue.Count == 0 {
+			t.Fatalf("unexpected issue %+v", issue)
+		}
+	}
+
+	// Nothing is reported when the baseline records every finding.
+	stdout.Reset()
+	d = &driver{dir: testdata}
+	if code := d.run([]string{"-key-naming-convention", "snake", "-baseline", path, "./key_naming_convention/camel"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d: %s%s", code, stdout.String(), stderr.String())
+	}
+
+	// Findings that are not recorded are still reported.
+	stdout.Reset()
+	d = &driver{dir: testdata}
+	if code := d.run([]string{"-key-naming-convention", "snake", "-capitalized-message", "true", "-baseline", path, "./key_naming_convention/camel"}, &stdout, &stderr); code != 3 {
+		t.Fatalf("exit code %d: %s", code, stderr.String())
+	}
+	if strings.Contains(stdout.String(), "should be in snake_case") || !strings.Contains(stdout.String(), "message 'message' should be capitalized") {
+		t.Fatalf("unexpected output: %s", stdout.String())
+	}
+}
+
+// testFinding returns a key-naming-convention finding for the key text at
+// the given line of /src/a.go.
+func testFinding(line int, text string) finding {
+	var f finding
+	f.Package = "p"
+	f.Category = "key-naming-convention"
+	f.Message = "key '" + text + "' should be in snake_case"
+	f.Posn = token.Position{Filename: "/src/a.go", Line: line}
+	f.End = f.Posn
+	f.Text = `"` + text + `"`
+	return f
+}
+
+func TestBaselineFilter(t *testing.T) {
+	t.Parallel()
+	b := newBaseline([]finding{testFinding(1, "userName"), testFinding(2, "userName"), testFinding(3, "requestID")})
+	if len(b.Issues) != 2 {
+		t.Fatalf("got %d issues, want 2", len(b.Issues))
+	}
+
+	// Findings match regardless of their line, up to the recorded count.
+	got := b.filter([]finding{testFinding(10, "userName"), testFinding(11, "userName"), testFinding(12, "userName"), testFinding(13, "requestID"), testFinding(14, "isValid")})
+	if len(got) != 2 || got[0].Posn.Line != 12 || got[1].Posn.Line != 14 {
+		t.Fatalf("unexpected findings %+v", got)
+	}
+}
+
+func TestReadBaselineVersion(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := os.WriteFile(path, []byte(`{"version": 2, "issues": []}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readBaseline(path); err == nil || !strings.Contains(err.Error(), "unsupported baseline version 2") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestUsesDriver(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"./..."}, false},
+		{[]string{"-fix", "./..."}, false},
+		{[]string{"-key-naming-convention", "snake", "-baseline", "b.json", "./..."}, true},
+		{[]string{"--write-baseline=b.json", "./..."}, true},
+		{[]string{"--", "-baseline"}, false},
+	} {
+		if got := usesDriver(tt.args); got != tt.want {
+			t.Errorf("usesDriver(%q) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestDriverCheckerFlags(t *testing.T) {
+	t.Parallel()
+	var stdout, stderr bytes.Buffer
+	d := &driver{dir: testdata}
+	if code := d.run([]string{"-fix", "-format=sarif", "./key_naming_convention/camel"}, &stdout, &stderr); code != 1 {
+		t.Fatalf("exit code %d, want 1", code)
+	}
+	if got, want := stderr.String(), "zaplint: -fix cannot be combined with any of -baseline, -write-baseline, -new-from-rev, -format, -html\n"; got != want {
+		t.Errorf("got error %q, want %q", got, want)
+	}
+
+	stdout.Reset()
+	d = &driver{dir: testdata}
+	if code := d.run([]string{"-V", "-format=text"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d, want 0", code)
+	}
+	if !strings.HasPrefix(stdout.String(), "zaplint version ") {
+		t.Errorf("unexpected output %q", stdout.String())
+	}
+}
+
+func TestDriverDependencyConfig(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod":            "module example.com/app\n\ngo 1.22\n\nrequire example.com/dep v0.0.0\n\nreplace example.com/dep => ./dep\n",
+		"main.go":           "package main\n\nimport \"example.com/dep\"\n\nfunc main() { dep.Run() }\n",
+		"dep/go.mod":        "module example.com/dep\n\ngo 1.22\n",
+		"dep/dep.go":        "package dep\n\nfunc Run() {}\n",
+		"dep/.zaplint.yaml": "some-future-option: true\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The configuration of a dependency is not read, even though the
+	// dependency is analyzed to compare keys across packages.
+	var stdout, stderr bytes.Buffer
+	d := &driver{dir: dir}
+	if code := d.run([]string{"-key-type-consistency=true", "-format=text", "./..."}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr.String())
+	}
+}
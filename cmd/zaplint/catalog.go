@@ -0,0 +1,124 @@
+package main
+
+import (
+	"cmp"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// catalogFormats are the formats of "zaplint catalog", keyed by name.
+var catalogFormats = map[string]func(w io.Writer, entries []record) error{
+	"markdown": writeCatalogMarkdown,
+	"csv":      writeCatalogCSV,
+}
+
+// runCatalog implements "zaplint catalog", which writes the constant log
+// messages of the packages matching the arguments along with where they
+// are emitted, so that a log line can be traced back to its source.
+func (d *driver) runCatalog(args []string, stdout, stderr io.Writer) int {
+	fset := flag.NewFlagSet("zaplint catalog", flag.ContinueOnError)
+	fset.SetOutput(stderr)
+	fset.Usage = func() {
+		fmt.Fprintln(fset.Output(), "usage: zaplint catalog [flags] packages...")
+		fset.PrintDefaults()
+	}
+	fset.BoolVar(&d.tests, "test", false, "indicates whether test files should be included, too")
+	fset.StringVar(&d.format, "format", "markdown", "output format: csv or markdown")
+	if err := fset.Parse(args); err != nil {
+		return 1
+	}
+	write, ok := catalogFormats[d.format]
+	if !ok {
+		fmt.Fprintf(stderr, "zaplint: -format=%s: unknown format (want csv or markdown)\n", d.format)
+		return 1
+	}
+
+	records, err := d.inventory(fset.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if err := write(stdout, catalog(records)); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// catalog returns the records with a constant message, ordered by message
+// and then by position.
+func catalog(records []record) []record {
+	entries := slices.DeleteFunc(slices.Clone(records), func(r record) bool { return r.Message == nil })
+	slices.SortStableFunc(entries, func(a, b record) int {
+		return cmp.Compare(*a.Message, *b.Message)
+	})
+	return entries
+}
+
+func catalogKeys(r record) []string {
+	var keys []string
+	for _, f := range r.Fields {
+		keys = append(keys, f.Key)
+	}
+	return keys
+}
+
+func catalogEmitter(r record) string {
+	if r.Function == "" {
+		return r.Package
+	}
+	return r.Package + "." + r.Function
+}
+
+func writeCatalogMarkdown(w io.Writer, entries []record) error {
+	var b strings.Builder
+	b.WriteString("| Message | Level | Emitted by | Keys | Location |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, r := range entries {
+		var keys []string
+		for _, key := range catalogKeys(r) {
+			keys = append(keys, markdownCode(key))
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s:%d |\n",
+			markdownCode(*r.Message), r.Level, markdownCode(catalogEmitter(r)), strings.Join(keys, ", "), r.File, r.Line)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCode formats s as inline code in a table cell, or as nothing if
+// it is empty.
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	s = strings.NewReplacer("|", `\|`, "\n", " ", "\r", " ").Replace(s)
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return fence + s + fence
+}
+
+func writeCatalogCSV(w io.Writer, entries []record) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"message", "level", "package", "function", "keys", "file", "line"}); err != nil {
+		return err
+	}
+	for _, r := range entries {
+		row := []string{*r.Message, r.Level, r.Package, r.Function, strings.Join(catalogKeys(r), " "), r.File, strconv.Itoa(r.Line)}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+func TestCatalog(t *testing.T) {
+	t.Parallel()
+	var stdout, stderr bytes.Buffer
+	d := &driver{dir: testdata}
+	if code := d.runCatalog([]string{"./inventory"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr.String())
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	// The header, the separator and a row per call with a constant message.
+	if len(lines) != 10 {
+		t.Fatalf("got %d lines, want 10:\n%s", len(lines), stdout.String())
+	}
+	want := "| `Server started` | info | `src/inventory.(*Server).Start` | `addr`, `timeout` | inventory/inventory.go:19 |"
+	if lines[5] != want {
+		t.Fatalf("got %s, want %s", lines[5], want)
+	}
+
+	stdout.Reset()
+	d = &driver{dir: testdata}
+	if code := d.runCatalog([]string{"-format", "csv", "./inventory"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr.String())
+	}
+	rows, err := csv.NewReader(&stdout).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 9 || strings.Join(rows[4], ",") != "Server started,info,src/inventory,(*Server).Start,addr timeout,inventory/inventory.go,19" {
+		t.Fatalf("unexpected rows %q", rows)
+	}
+}
+
+func TestMarkdownCode(t *testing.T) {
+	t.Parallel()
+	for s, want := range map[string]string{
+		"":          "",
+		"a|b":       "`a\\|b`",
+		"use `x`":   "`` use `x` ``",
+		"two\nline": "`two line`",
+	} {
+		if got := markdownCode(s); got != want {
+			t.Errorf("markdownCode(%q) = %q, want %q", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// reference is a log message or key that an alert rule relies on.
+type reference struct {
+	Posn  string // file:line:column of the reference
+	Alert string // The name of the enclosing alert rule, if any.
+	Kind  string // "message" or "key"
+	Value string
+}
+
+// refsConfig tells which fields of the reference files hold log messages
+// and keys.
+type refsConfig struct {
+	messageFields []string
+	keyFields     []string
+	nameFields    []string
+}
+
+// runCheckRefs implements "zaplint check-refs", which reports the log
+// messages and keys referenced by local files, such as alerting rules, that
+// none of the packages matching the arguments log anymore.
+func (d *driver) runCheckRefs(args []string, stdout, stderr io.Writer) int {
+	fset := flag.NewFlagSet("zaplint check-refs", flag.ContinueOnError)
+	fset.SetOutput(stderr)
+	fset.Usage = func() {
+		fmt.Fprintln(fset.Output(), "usage: zaplint check-refs [flags] files packages...")
+		fmt.Fprintln(fset.Output(), "\nfiles is a comma-separated list of YAML files or glob patterns.")
+		fset.PrintDefaults()
+	}
+	cfg := refsConfig{
+		messageFields: []string{"message", "msg"},
+		keyFields:     []string{"keys", "key"},
+		nameFields:    []string{"alert", "name"},
+	}
+	fset.Func("message-fields", "comma-separated names of the fields that hold log messages (default message,msg)", func(s string) error {
+		cfg.messageFields = strings.Split(s, ",")
+		return nil
+	})
+	fset.Func("key-fields", "comma-separated names of the fields that hold log keys (default keys,key)", func(s string) error {
+		cfg.keyFields = strings.Split(s, ",")
+		return nil
+	})
+	fset.Func("name-fields", "comma-separated names of the fields that name alert rules (default alert,name)", func(s string) error {
+		cfg.nameFields = strings.Split(s, ",")
+		return nil
+	})
+	fset.BoolVar(&d.tests, "test", false, "indicates whether log calls in test files count, too")
+	if err := fset.Parse(args); err != nil {
+		return 1
+	}
+	if fset.NArg() < 2 {
+		fset.Usage()
+		return 1
+	}
+
+	refs, err := readReferences(fset.Arg(0), cfg)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	records, err := d.inventory(fset.Args()[1:])
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	stale := staleReferences(refs, records)
+	for _, ref := range stale {
+		alert := ""
+		if ref.Alert != "" {
+			alert = fmt.Sprintf("alert '%s' ", ref.Alert)
+		}
+		fmt.Fprintf(stdout, "%s: %sreferences %s '%s', which is not logged anywhere\n", ref.Posn, alert, ref.Kind, ref.Value)
+	}
+	if len(stale) > 0 {
+		return 3
+	}
+	return 0
+}
+
+// staleReferences returns the references to messages and keys that are not
+// found in records. The templates of printf-style methods, such as Infof,
+// match the messages they format, as well as themselves.
+func staleReferences(refs []reference, records []record) []reference {
+	messages := make(map[string]bool)
+	keys := make(map[string]bool)
+	var templates []*regexp.Regexp
+	for _, r := range records {
+		if r.Message != nil {
+			messages[*r.Message] = true
+			if r.Logger == "SugaredLogger" && strings.HasSuffix(r.Method, "f") && strings.Contains(*r.Message, "%") {
+				templates = append(templates, templateRegexp(*r.Message))
+			}
+		}
+		for _, f := range r.Fields {
+			keys[f.Key] = true
+		}
+	}
+
+	logged := func(message string) bool {
+		return messages[message] || slices.ContainsFunc(templates, func(re *regexp.Regexp) bool {
+			return re.MatchString(message)
+		})
+	}
+	var stale []reference
+	for _, ref := range refs {
+		if ref.Kind == "message" && !logged(ref.Value) || ref.Kind == "key" && !keys[ref.Value] {
+			stale = append(stale, ref)
+		}
+	}
+	return stale
+}
+
+// templateRegexp returns a regular expression matching the messages that
+// the printf template formats, where each verb may expand to any text.
+func templateRegexp(template string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for {
+		i := strings.IndexByte(template, '%')
+		if i < 0 {
+			break
+		}
+		b.WriteString(regexp.QuoteMeta(template[:i]))
+		template = template[i+1:]
+		// Skip the flags, width, precision and argument index of the verb.
+		j := strings.IndexFunc(template, func(r rune) bool {
+			return !strings.ContainsRune("+-# 0123456789.*[]", r)
+		})
+		if j < 0 {
+			template = ""
+			break
+		}
+		if template[j] == '%' {
+			b.WriteString("%")
+		} else {
+			b.WriteString("(?s:.*)")
+		}
+		_, size := utf8.DecodeRuneInString(template[j:])
+		template = template[j+size:]
+	}
+	b.WriteString(regexp.QuoteMeta(template))
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// readReferences returns the references in the files matching the
+// comma-separated list of paths and glob patterns.
+func readReferences(list string, cfg refsConfig) ([]reference, error) {
+	var refs []reference
+	for _, pattern := range strings.Split(list, ",") {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("zaplint: %s: %w", pattern, err)
+		}
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("zaplint: %s: %w", pattern, os.ErrNotExist)
+		}
+		for _, path := range paths {
+			r, err := readReferenceFile(path, cfg)
+			if err != nil {
+				return nil, err
+			}
+			refs = append(refs, r...)
+		}
+	}
+	return refs, nil
+}
+
+// readReferenceFile returns the references in the YAML file at path, which
+// may hold several documents.
+func readReferenceFile(path string, cfg refsConfig) ([]reference, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("zaplint: %w", err)
+	}
+	defer f.Close()
+
+	var refs []reference
+	dec := yaml.NewDecoder(f)
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); errors.Is(err, io.EOF) {
+			return refs, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("zaplint: %s: %w", path, err)
+		}
+		refs = cfg.collect(refs, path, &doc, "")
+	}
+}
+
+// collect appends the references found in node to refs. alert is the name
+// of the alert rule that node belongs to.
+func (cfg refsConfig) collect(refs []reference, path string, node *yaml.Node, alert string) []reference {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			refs = cfg.collect(refs, path, child, alert)
+		}
+	case yaml.AliasNode:
+		// Aliased content is reported where it is defined.
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if slices.Contains(cfg.nameFields, key.Value) && value.Kind == yaml.ScalarNode {
+				alert = value.Value
+				break
+			}
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			var kind string
+			switch {
+			case slices.Contains(cfg.messageFields, key.Value):
+				kind = "message"
+			case slices.Contains(cfg.keyFields, key.Value):
+				kind = "key"
+			default:
+				refs = cfg.collect(refs, path, value, alert)
+				continue
+			}
+			for _, scalar := range scalars(value) {
+				refs = append(refs, reference{
+					Posn:  fmt.Sprintf("%s:%d:%d", path, scalar.Line, scalar.Column),
+					Alert: alert,
+					Kind:  kind,
+					Value: scalar.Value,
+				})
+			}
+		}
+	}
+	return refs
+}
+
+// scalars returns node if it is a scalar, or the scalars it contains if it
+// is a sequence.
+func scalars(node *yaml.Node) []*yaml.Node {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return []*yaml.Node{node}
+	case yaml.SequenceNode:
+		var nodes []*yaml.Node
+		for _, child := range node.Content {
+			nodes = append(nodes, scalars(child)...)
+		}
+		return nodes
+	default:
+		return nil
+	}
+}
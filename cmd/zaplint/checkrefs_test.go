@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckRefs(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	alerts := filepath.Join(dir, "alerts.yaml")
+	content := `groups:
+  - name: server
+    rules:
+      - alert: ServerDown
+        message: Server stopped
+        keys: [reason, code]
+      - alert: ServerStopping
+        message: Stopping for maintenance
+      - alert: StopTemplate
+        message: Stopping %s
+      - alert: StartFailed
+        message: Start failure
+        keys:
+          - error
+          - exit_code
+---
+alert: Slow
+msg: Slow start
+key: elapsed
+`
+	if err := os.WriteFile(alerts, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	d := &driver{dir: testdata}
+	if code := d.runCheckRefs([]string{alerts, "./inventory"}, &stdout, &stderr); code != 3 {
+		t.Fatalf("exit code %d: %s", code, stderr.String())
+	}
+	want := []string{
+		alerts + ":12:18: alert 'StartFailed' references message 'Start failure', which is not logged anywhere",
+		alerts + ":15:13: alert 'StartFailed' references key 'exit_code', which is not logged anywhere",
+		alerts + ":19:6: alert 'Slow' references key 'elapsed', which is not logged anywhere",
+	}
+	if got := strings.Split(strings.TrimSpace(stdout.String()), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// The fields are configurable.
+	stdout.Reset()
+	d = &driver{dir: testdata}
+	args := []string{"-message-fields", "summary", "-key-fields", "labels", filepath.Join(dir, "*.yaml"), "./inventory"}
+	if code := d.runCheckRefs(args, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d: %s%s", code, stdout.String(), stderr.String())
+	}
+
+	if code := d.runCheckRefs([]string{filepath.Join(dir, "missing.yaml"), "./inventory"}, &stdout, &stderr); code != 1 {
+		t.Fatalf("exit code %d, want 1", code)
+	}
+}
+
+func TestTemplateRegexp(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		template, message string
+		want              bool
+	}{
+		{"User %s failed", "User alice failed", true},
+		{"User %s failed", "User %s failed", true},
+		{"User %s failed", "User alice succeeded", false},
+		{"Took %.2fs (%d%%)", "Took 1.50s (10%)", true},
+		{"Took %.2fs (%d%%)", "Took 1.50s (10)", false},
+		{"Retry %[1]*d.", "Retry 3.", true},
+		{"Retry %[1]*d.", "Retry 3x", false},
+	} {
+		if got := templateRegexp(tt.template).MatchString(tt.message); got != tt.want {
+			t.Errorf("templateRegexp(%q).MatchString(%q) = %v, want %v", tt.template, tt.message, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// writeCheckstyle writes the findings of r in the checkstyle XML format,
+// with one file element per file. The rule of each finding is its source,
+// qualified by "zaplint.".
+func writeCheckstyle(w io.Writer, r *report) error {
+	out := checkstyleReport{Version: "4.3"}
+	index := make(map[string]int)
+	for _, f := range r.findings {
+		name := r.path(f.Posn.Filename)
+		i, ok := index[name]
+		if !ok {
+			i = len(out.Files)
+			index[name] = i
+			out.Files = append(out.Files, checkstyleFile{Name: name})
+		}
+		out.Files[i].Errors = append(out.Files[i].Errors, checkstyleError{
+			Line:     f.Posn.Line,
+			Column:   r.column(f.Posn),
+			Severity: checkstyleSeverity(ruleDoc(f.Category).Level),
+			Message:  f.Message,
+			Source:   "zaplint." + f.Category,
+		})
+	}
+	return writeXML(w, out)
+}
+
+// checkstyleSeverity converts a SARIF level to a checkstyle severity.
+func checkstyleSeverity(level string) string {
+	if level == "note" {
+		return "info"
+	}
+	return level
+}
+
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestCheckstyle(t *testing.T) {
+	t.Parallel()
+	var stdout, stderr bytes.Buffer
+	d := &driver{dir: testdata}
+	if code := d.run([]string{"-format", "checkstyle", "-capitalized-message", "true", "./capitalized_message"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr.String())
+	}
+
+	var out checkstyleReport
+	if err := xml.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Files) == 0 {
+		t.Fatal("no files")
+	}
+	for _, file := range out.Files {
+		if !strings.HasPrefix(file.Name, "capitalized_message/") || len(file.Errors) == 0 {
+			t.Fatalf("unexpected file %+v", file)
+		}
+		for _, e := range file.Errors {
+			if e.Line == 0 || e.Column == 0 || e.Severity != "warning" || e.Source != "zaplint.capitalized-message" || !strings.HasSuffix(e.Message, "should be capitalized") {
+				t.Fatalf("unexpected error %+v", e)
+			}
+		}
+	}
+}
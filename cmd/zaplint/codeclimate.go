@@ -0,0 +1,84 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// codeClimateIssue is an issue of the Code Climate format, in the subset
+// that GitLab Code Quality reports use.
+type codeClimateIssue struct {
+	Type        string              `json:"type"`
+	CheckName   string              `json:"check_name"`
+	Description string              `json:"description"`
+	Categories  []string            `json:"categories"`
+	Severity    string              `json:"severity"`
+	Fingerprint string              `json:"fingerprint"`
+	Location    codeClimateLocation `json:"location"`
+}
+
+type codeClimateLocation struct {
+	Path  string           `json:"path"`
+	Lines codeClimateLines `json:"lines"`
+}
+
+type codeClimateLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end"`
+}
+
+// writeCodeClimate writes the findings of r as a Code Climate JSON array,
+// as consumed by GitLab Code Quality.
+//
+// Fingerprints are computed from the rule, the file and the source text and
+// message of each finding rather than its line, so that merge requests
+// only show the issues they introduce or resolve. Findings that would share
+// a fingerprint, such as the same key logged twice in a file, are told
+// apart by their order within the file.
+func writeCodeClimate(w io.Writer, r *report) error {
+	issues := []codeClimateIssue{}
+	seen := make(map[string]int)
+	for _, f := range r.findings {
+		path := r.path(f.Posn.Filename)
+		parts := []string{f.Category, path, f.Text, strings.Join(strings.Fields(f.Message), " ")}
+		key := strings.Join(parts, "\x00")
+		if n := seen[key]; n > 0 {
+			parts = append(parts, strconv.Itoa(n))
+		}
+		seen[key]++
+		sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+
+		issues = append(issues, codeClimateIssue{
+			Type:        "issue",
+			CheckName:   f.Category,
+			Description: f.Message,
+			Categories:  []string{"Style"},
+			Severity:    codeClimateSeverity(ruleDoc(f.Category).Level),
+			Fingerprint: hex.EncodeToString(sum[:16]),
+			Location: codeClimateLocation{
+				Path:  path,
+				Lines: codeClimateLines{Begin: f.Posn.Line, End: f.End.Line},
+			},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(issues)
+}
+
+// codeClimateSeverity converts a SARIF level to a Code Climate severity.
+func codeClimateSeverity(level string) string {
+	switch level {
+	case "error":
+		return "major"
+	case "note":
+		return "info"
+	default:
+		return "minor"
+	}
+}
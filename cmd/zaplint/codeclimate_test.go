@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestCodeClimate(t *testing.T) {
+	t.Parallel()
+	var stdout, stderr bytes.Buffer
+	d := &driver{dir: testdata}
+	if code := d.run([]string{"-format", "codeclimate", "-key-naming-convention", "snake", "./key_naming_convention/camel"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr.String())
+	}
+
+	var issues []codeClimateIssue
+	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) == 0 {
+		t.Fatal("no issues")
+	}
+	fingerprints := make(map[string]bool)
+	for _, issue := range issues {
+		if issue.CheckName != "key-naming-convention" || issue.Severity != "minor" || issue.Location.Path == "" || issue.Location.Lines.Begin == 0 {
+			t.Fatalf("unexpected issue %+v", issue)
+		}
+		if fingerprints[issue.Fingerprint] {
+			t.Fatalf("duplicate fingerprint %s", issue.Fingerprint)
+		}
+		fingerprints[issue.Fingerprint] = true
+	}
+}
+
+func TestCodeClimateFingerprint(t *testing.T) {
+	t.Parallel()
+	fingerprints := func(findings ...finding) []string {
+		var buf bytes.Buffer
+		if err := writeCodeClimate(&buf, &report{root: "/src", findings: findings}); err != nil {
+			t.Fatal(err)
+		}
+		var issues []codeClimateIssue
+		if err := json.Unmarshal(buf.Bytes(), &issues); err != nil {
+			t.Fatal(err)
+		}
+		var fps []string
+		for _, issue := range issues {
+			fps = append(fps, issue.Fingerprint)
+		}
+		return fps
+	}
+
+	before := fingerprints(testFinding(1, "userName"), testFinding(2, "userName"))
+	if before[0] == before[1] {
+		t.Fatal("repeated findings share a fingerprint")
+	}
+	// Moving code around keeps the fingerprints.
+	after := fingerprints(testFinding(5, "requestID"), testFinding(10, "userName"), testFinding(20, "userName"))
+	if after[1] != before[0] || after[2] != before[1] {
+		t.Fatalf("fingerprints changed: %v, %v", before, after)
+	}
+}
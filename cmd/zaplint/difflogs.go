@@ -0,0 +1,275 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Kinds of logChange.
+const (
+	changeRemovedMessage = "removed-message"
+	changeRenamedMessage = "renamed-message"
+	changeRemovedKey     = "removed-key"
+	changeKeyType        = "changed-key-type"
+)
+
+// logChange is a change of the logs emitted by the code between two
+// revisions that may break the parsers of those logs.
+type logChange struct {
+	Kind       string `json:"kind"`
+	Message    string `json:"message"`
+	NewMessage string `json:"new_message,omitempty"`
+	Key        string `json:"key,omitempty"`
+	OldType    string `json:"old_type,omitempty"`
+	NewType    string `json:"new_type,omitempty"`
+
+	// The location of the change: in the head revision, or in the base
+	// revision for what was removed.
+	Rev  string `json:"rev"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+func (c logChange) String() string {
+	var what string
+	switch c.Kind {
+	case changeRemovedMessage:
+		what = fmt.Sprintf("message '%s' was removed", c.Message)
+	case changeRenamedMessage:
+		what = fmt.Sprintf("message '%s' was renamed to '%s'", c.Message, c.NewMessage)
+	case changeRemovedKey:
+		what = fmt.Sprintf("key '%s' was removed from message '%s'", c.Key, c.Message)
+	case changeKeyType:
+		what = fmt.Sprintf("key '%s' of message '%s' changed type from %s to %s", c.Key, c.Message, c.OldType, c.NewType)
+	}
+	return fmt.Sprintf("%s:%s:%d: %s", c.Rev, c.File, c.Line, what)
+}
+
+// runDiffLogs implements "zaplint diff-logs", which compares the log calls
+// of two git revisions and reports the changes that may break the parsers
+// of the logs.
+func (d *driver) runDiffLogs(args []string, stdout, stderr io.Writer) int {
+	fset := flag.NewFlagSet("zaplint diff-logs", flag.ContinueOnError)
+	fset.SetOutput(stderr)
+	fset.Usage = func() {
+		fmt.Fprintln(fset.Output(), "usage: zaplint diff-logs [flags] base-rev head-rev [packages...]")
+		fset.PrintDefaults()
+	}
+	fset.BoolVar(&d.tests, "test", false, "indicates whether log calls in test files should be compared, too")
+	fset.StringVar(&d.format, "format", "text", "output format: json or text")
+	if err := fset.Parse(args); err != nil {
+		return 1
+	}
+	if fset.NArg() < 2 {
+		fset.Usage()
+		return 1
+	}
+	if d.format != "text" && d.format != "json" {
+		fmt.Fprintf(stderr, "zaplint: -format=%s: unknown format (want json or text)\n", d.format)
+		return 1
+	}
+	base, head, patterns := fset.Arg(0), fset.Arg(1), fset.Args()[2:]
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	baseRecords, err := d.inventoryAt(base, patterns)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	headRecords, err := d.inventoryAt(head, patterns)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	changes := diffLogs(base, baseRecords, head, headRecords)
+
+	if d.format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(struct {
+			Base    string      `json:"base"`
+			Head    string      `json:"head"`
+			Changes []logChange `json:"changes"`
+		}{base, head, changes})
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	for _, c := range changes {
+		fmt.Fprintln(stdout, c)
+	}
+	if len(changes) > 0 {
+		return 3
+	}
+	return 0
+}
+
+// inventoryAt returns the inventory of the packages matching patterns at
+// the git revision rev, checked out in a temporary worktree. Patterns are
+// resolved in the directory of the worktree that corresponds to the one
+// packages are loaded from.
+func (d *driver) inventoryAt(rev string, patterns []string) ([]record, error) {
+	root, err := d.root()
+	if err != nil {
+		return nil, err
+	}
+	top, err := git(root, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	top = strings.TrimSpace(top)
+	real, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return nil, fmt.Errorf("zaplint: %w", err)
+	}
+	rel, err := filepath.Rel(top, real)
+	if err != nil {
+		return nil, fmt.Errorf("zaplint: %w", err)
+	}
+
+	tmp, err := os.MkdirTemp("", "zaplint-diff-logs-")
+	if err != nil {
+		return nil, fmt.Errorf("zaplint: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+	worktree := filepath.Join(tmp, "worktree")
+	if _, err := git(top, "worktree", "add", "--detach", "--quiet", worktree, rev); err != nil {
+		return nil, err
+	}
+	defer git(top, "worktree", "remove", "--force", worktree)
+
+	wd := &driver{dir: filepath.Join(worktree, rel), tests: d.tests}
+	return wd.inventory(patterns)
+}
+
+// logMessage is what the records of a constant message have in common.
+type logMessage struct {
+	text     string
+	first    record              // The first call that logs the message.
+	emitters []string            // The functions that log the message, as package.function.
+	keys     map[string][]string // The types of each key, as in fieldType.
+	keyAt    map[string]record   // The first call that logs each key.
+}
+
+// logMessages groups records by constant message.
+func logMessages(records []record) map[string]*logMessage {
+	messages := make(map[string]*logMessage)
+	for _, r := range records {
+		if r.Message == nil {
+			continue
+		}
+		m, ok := messages[*r.Message]
+		if !ok {
+			m = &logMessage{text: *r.Message, first: r, keys: make(map[string][]string), keyAt: make(map[string]record)}
+			messages[*r.Message] = m
+		}
+		if emitter := catalogEmitter(r); !slices.Contains(m.emitters, emitter) {
+			m.emitters = append(m.emitters, emitter)
+		}
+		for _, f := range r.Fields {
+			if _, ok := m.keyAt[f.Key]; !ok {
+				m.keyAt[f.Key] = r
+			}
+			if t := fieldType(f); !slices.Contains(m.keys[f.Key], t) {
+				m.keys[f.Key] = append(m.keys[f.Key], t)
+			}
+		}
+	}
+	for _, m := range messages {
+		for _, types := range m.keys {
+			slices.Sort(types)
+		}
+	}
+	return messages
+}
+
+// fieldType returns how the value of f is encoded: the field constructor it
+// is encoded with or, when that is not known, its Go type.
+func fieldType(f recordField) string {
+	if f.Encoding != "" {
+		return f.Encoding
+	}
+	return f.Type
+}
+
+// diffLogs returns the changes from the logs of base to those of head.
+//
+// A message that is no longer logged is reported as renamed when exactly
+// one new message is logged by the same functions instead, and no other
+// message of those functions is removed, and as removed otherwise. The keys of the messages logged by both revisions, including
+// renamed ones, are compared too.
+func diffLogs(base string, baseRecords []record, head string, headRecords []record) []logChange {
+	old, cur := logMessages(baseRecords), logMessages(headRecords)
+
+	// The messages only logged by one revision, keyed by emitters.
+	added, removed := make(map[string][]*logMessage), make(map[string]int)
+	for text, m := range cur {
+		if _, ok := old[text]; !ok {
+			key := strings.Join(m.emitters, "\n")
+			added[key] = append(added[key], m)
+		}
+	}
+	for text, m := range old {
+		if _, ok := cur[text]; !ok {
+			removed[strings.Join(m.emitters, "\n")]++
+		}
+	}
+
+	var changes []logChange
+	for _, m := range old {
+		n, ok := cur[m.text]
+		if !ok {
+			key := strings.Join(m.emitters, "\n")
+			candidates := added[key]
+			if len(candidates) != 1 || removed[key] != 1 {
+				changes = append(changes, logChange{Kind: changeRemovedMessage, Message: m.text, Rev: base, File: m.first.File, Line: m.first.Line})
+				continue
+			}
+			n = candidates[0]
+			changes = append(changes, logChange{Kind: changeRenamedMessage, Message: m.text, NewMessage: n.text, Rev: head, File: n.first.File, Line: n.first.Line})
+		}
+
+		for key, oldTypes := range m.keys {
+			newTypes, ok := n.keys[key]
+			if !ok {
+				at := m.keyAt[key]
+				changes = append(changes, logChange{Kind: changeRemovedKey, Message: m.text, Key: key, Rev: base, File: at.File, Line: at.Line})
+				continue
+			}
+			if !slices.Equal(oldTypes, newTypes) {
+				at := n.keyAt[key]
+				changes = append(changes, logChange{
+					Kind:    changeKeyType,
+					Message: m.text,
+					Key:     key,
+					OldType: strings.Join(oldTypes, "|"),
+					NewType: strings.Join(newTypes, "|"),
+					Rev:     head,
+					File:    at.File,
+					Line:    at.Line,
+				})
+			}
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b logChange) int {
+		return cmp.Or(
+			strings.Compare(a.Message, b.Message),
+			strings.Compare(a.Kind, b.Kind),
+			strings.Compare(a.Key, b.Key),
+		)
+	})
+	return changes
+}
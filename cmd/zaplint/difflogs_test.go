@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffLogs(t *testing.T) {
+	t.Parallel()
+	newRecord := func(function, message string, fields ...recordField) record {
+		return record{Package: "p", File: "p/p.go", Line: len(message), Function: function, Message: &message, Fields: fields}
+	}
+	base := []record{
+		newRecord("f", "Request handled", recordField{Key: "user_id", Constructor: "zap.String", Encoding: "zap.String"}, recordField{Key: "path", Constructor: "zap.String", Encoding: "zap.String"}),
+		newRecord("g", "Cache miss", recordField{Key: "key", Constructor: "zap.String", Encoding: "zap.String"}),
+		newRecord("h", "Shutting down", recordField{Key: "reason", Type: "string", Encoding: "zap.String"}),
+		newRecord("h", "Closing", recordField{Key: "conns", Type: "int", Encoding: "zap.Int"}),
+		newRecord("j", "Retrying"),
+		newRecord("j", "Retry failed"),
+	}
+	head := []record{
+		newRecord("f", "Request handled", recordField{Key: "user_id", Constructor: "zap.Int64", Encoding: "zap.Int64"}),
+		newRecord("g", "Cache lookup missed", recordField{Key: "key", Constructor: "zap.String", Encoding: "zap.String"}, recordField{Key: "size", Constructor: "zap.Int", Encoding: "zap.Int"}),
+		newRecord("h", "Stopping"),
+		newRecord("h", "Stopped"),
+		newRecord("i", "Closing", recordField{Key: "conns", Constructor: "zap.Any", Type: "int", Encoding: "zap.Int"}),
+		newRecord("j", "Retry attempted"),
+	}
+
+	var got []string
+	for _, c := range diffLogs("base", base, "head", head) {
+		got = append(got, c.String())
+	}
+	want := []string{
+		"head:p/p.go:19: message 'Cache miss' was renamed to 'Cache lookup missed'",
+		"head:p/p.go:15: key 'user_id' of message 'Request handled' changed type from zap.String to zap.Int64",
+		"base:p/p.go:15: key 'path' was removed from message 'Request handled'",
+		// Two messages of j are removed for one new message.
+		"base:p/p.go:12: message 'Retry failed' was removed",
+		"base:p/p.go:8: message 'Retrying' was removed",
+		"base:p/p.go:13: message 'Shutting down' was removed",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestDiffLogsWorktrees(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+		if _, err := git(dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"go.mod", "go.sum"} {
+		content, err := os.ReadFile(filepath.Join(testdata, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		write(name, string(content))
+	}
+
+	const program = `package main
+
+import "go.uber.org/zap"
+
+func main() {
+	logger := zap.NewExample()
+	logger.Info(%q, zap.%s("user_id", %s))
+}
+`
+	run("init", "-q")
+	write("main.go", fmt.Sprintf(program, "User logged in", "String", `"42"`))
+	run("add", ".")
+	run("commit", "-q", "-m", "base")
+	write("main.go", fmt.Sprintf(program, "User logged in", "Int64", "42"))
+	run("commit", "-q", "-a", "-m", "head")
+
+	var stdout, stderr bytes.Buffer
+	d := &driver{dir: dir}
+	if code := d.runDiffLogs([]string{"-format", "json", "HEAD~1", "HEAD"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr.String())
+	}
+	var out struct {
+		Changes []logChange `json:"changes"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	want := logChange{Kind: changeKeyType, Message: "User logged in", Key: "user_id", OldType: "zap.String", NewType: "zap.Int64", Rev: "HEAD", File: "main.go", Line: 7}
+	if len(out.Changes) != 1 || out.Changes[0] != want {
+		t.Fatalf("got %+v, want %+v", out.Changes, want)
+	}
+
+	// The worktrees are removed.
+	worktrees, err := git(dir, "worktree", "list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(worktrees, "\n"); n != 1 {
+		t.Fatalf("got %d worktrees:\n%s", n, worktrees)
+	}
+}
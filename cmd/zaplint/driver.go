@@ -0,0 +1,322 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/rleungx/zaplint"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+// driverFlags are the flags that zaplint handles with its own driver rather
+// than singlechecker, since they need the diagnostics of the whole run.
+var driverFlags = []string{"baseline", "write-baseline", "new-from-rev", "format", "html"}
+
+// checkerFlags are the flags of singlechecker that the driver does not
+// support.
+var checkerFlags = []string{"fix", "diff", "json", "c", "flags", "debug", "cpuprofile", "memprofile", "trace"}
+
+// usesDriver reports whether args contain any of the driverFlags.
+func usesDriver(args []string) bool {
+	return slices.ContainsFunc(flagNames(args), func(name string) bool {
+		return slices.Contains(driverFlags, name)
+	})
+}
+
+// flagNames returns the names of the flags in args, up to "--".
+func flagNames(args []string) []string {
+	var names []string
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		names = append(names, name)
+	}
+	return names
+}
+
+// finding is a diagnostic reported by zaplint, resolved against the file
+// set of its package.
+type finding struct {
+	Package string
+	Posn    token.Position
+	End     token.Position
+	Text    string // The source text of the reported range.
+	Fixes   []fix
+	analysis.Diagnostic
+}
+
+// fix is a suggested fix of a finding, resolved like the finding itself.
+type fix struct {
+	Message string
+	Edits   []edit
+}
+
+// edit replaces the text from Start to End with NewText.
+type edit struct {
+	Start, End token.Position
+	NewText    string
+}
+
+// driver runs the zaplint analyzer over packages and post-processes the
+// findings before printing them.
+type driver struct {
+	dir           string // The directory to load packages from; the current one if empty.
+	opts          *zaplint.Options
+	tests         bool
+	baseline      string
+	writeBaseline string
+	newFromRev    string
+	format        string
+	html          string
+	files         map[string][]byte // The content of the reported files.
+}
+
+// run parses args and runs the driver, returning the exit code: 0 if there
+// were no findings, 3 if there were, and 1 on errors, as singlechecker does.
+func (d *driver) run(args []string, stdout, stderr io.Writer) int {
+	for _, name := range flagNames(args) {
+		if slices.Contains(checkerFlags, name) {
+			fmt.Fprintf(stderr, "zaplint: -%s cannot be combined with any of -%s\n", name, strings.Join(driverFlags, ", -"))
+			return 1
+		}
+	}
+
+	d.opts = &zaplint.Options{}
+	analyzer := zaplint.New(d.opts)
+
+	fset := flag.NewFlagSet("zaplint", flag.ContinueOnError)
+	fset.SetOutput(stderr)
+	analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		fset.Var(f.Value, f.Name, f.Usage)
+	})
+	fset.BoolVar(&d.tests, "test", true, "indicates whether test files should be analyzed, too")
+	fset.StringVar(&d.baseline, "baseline", "", "only report findings that are not recorded in the given baseline file")
+	fset.StringVar(&d.writeBaseline, "write-baseline", "", "record all findings in the given baseline file instead of reporting them")
+	fset.StringVar(&d.newFromRev, "new-from-rev", "", "only report findings on lines changed since the given git revision")
+	fset.StringVar(&d.format, "format", "text", "output format: "+strings.Join(formatNames(), ", "))
+	fset.StringVar(&d.html, "html", "", "also write the findings as an HTML report to the given file")
+	printVersion := fset.Bool("V", false, "print version and exit")
+	if err := fset.Parse(args); err != nil {
+		return 1
+	}
+	if *printVersion {
+		fmt.Fprintln(stdout, versionString())
+		return 0
+	}
+	write, ok := formats[d.format]
+	if !ok {
+		fmt.Fprintf(stderr, "zaplint: -format=%s: unknown format (want one of %s)\n", d.format, strings.Join(formatNames(), ", "))
+		return 1
+	}
+
+	findings, err := d.analyze(analyzer, fset.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	if d.writeBaseline != "" {
+		if err := writeBaseline(d.writeBaseline, findings); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	if d.newFromRev != "" {
+		findings, err = d.filterChanged(findings)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+
+	if d.baseline != "" {
+		b, err := readBaseline(d.baseline)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		findings = b.filter(findings)
+	}
+
+	r, err := d.report(analyzer, findings)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if d.html != "" {
+		if err := writeHTMLFile(d.html, r); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+	if err := write(stdout, r); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	// Like singlechecker with -json, machine-readable formats only fail on
+	// errors, so that the report can be processed.
+	if d.format == "text" && len(findings) > 0 {
+		return 3
+	}
+	return 0
+}
+
+// root returns the absolute directory that packages are loaded from.
+func (d *driver) root() (string, error) {
+	root, err := filepath.Abs(cmp.Or(d.dir, "."))
+	if err != nil {
+		return "", fmt.Errorf("zaplint: %w", err)
+	}
+	return root, nil
+}
+
+// report returns the report of the findings of analyzer, with paths
+// relative to the directory the packages were loaded from.
+func (d *driver) report(analyzer *analysis.Analyzer, findings []finding) (*report, error) {
+	root, err := d.root()
+	if err != nil {
+		return nil, err
+	}
+	return &report{root: root, url: analyzer.URL, findings: findings, files: d.files}, nil
+}
+
+// load loads the packages matching patterns and runs analyzer on them.
+func (d *driver) load(analyzer *analysis.Analyzer, patterns []string) (*checker.Graph, error) {
+	// The analyzer tells the packages of the main module from dependencies
+	// by their module.
+	cfg := &packages.Config{Mode: packages.LoadAllSyntax | packages.NeedModule, Dir: d.dir, Tests: d.tests}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if n := packages.PrintErrors(pkgs); n > 0 {
+		return nil, fmt.Errorf("zaplint: %d errors while loading packages", n)
+	}
+	return checker.Analyze([]*analysis.Analyzer{analyzer}, pkgs, nil)
+}
+
+// analyze loads the packages matching patterns and returns the findings of
+// analyzer, ordered by position.
+func (d *driver) analyze(analyzer *analysis.Analyzer, patterns []string) ([]finding, error) {
+	graph, err := d.load(analyzer, patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []finding
+	seen := make(map[string]bool)
+	d.files = make(map[string][]byte)
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			return nil, act.Err
+		}
+		for _, diag := range act.Diagnostics {
+			f := newFinding(act.Package, diag, d.files)
+			// Packages and their test variants share files, so the
+			// same diagnostic may be reported more than once.
+			key := fmt.Sprintf("%s\x00%s\x00%s", f.Posn, f.Category, f.Message)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			findings = append(findings, f)
+		}
+	}
+
+	slices.SortFunc(findings, func(a, b finding) int {
+		if c := strings.Compare(a.Posn.Filename, b.Posn.Filename); c != 0 {
+			return c
+		}
+		if c := a.Posn.Offset - b.Posn.Offset; c != 0 {
+			return c
+		}
+		return strings.Compare(a.Message, b.Message)
+	})
+	return findings, nil
+}
+
+// filterChanged returns the findings on lines changed since d.newFromRev.
+func (d *driver) filterChanged(findings []finding) ([]finding, error) {
+	dir := d.dir
+	if dir == "" {
+		dir = "."
+	}
+	c, err := changedLines(dir, d.newFromRev)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(findings, func(f finding) bool {
+		// git reports paths with symbolic links resolved.
+		filename, err := filepath.EvalSymlinks(f.Posn.Filename)
+		if err != nil {
+			filename = f.Posn.Filename
+		}
+		return !c.contains(filename, f.Posn.Line, f.End.Line)
+	}), nil
+}
+
+// newFinding resolves diag, reading the source files it refers to through
+// the files cache.
+func newFinding(pkg *packages.Package, diag analysis.Diagnostic, files map[string][]byte) finding {
+	f := finding{
+		Package:    pkg.PkgPath,
+		Posn:       pkg.Fset.Position(diag.Pos),
+		Diagnostic: diag,
+	}
+
+	end := diag.End
+	if !end.IsValid() {
+		end = diag.Pos
+	}
+	f.End = pkg.Fset.Position(end)
+
+	content, ok := files[f.Posn.Filename]
+	if !ok {
+		content, _ = os.ReadFile(f.Posn.Filename)
+		files[f.Posn.Filename] = content
+	}
+	for _, sf := range diag.SuggestedFixes {
+		fx := fix{Message: sf.Message}
+		for _, te := range sf.TextEdits {
+			end := te.End
+			if !end.IsValid() {
+				end = te.Pos
+			}
+			fx.Edits = append(fx.Edits, edit{
+				Start:   pkg.Fset.Position(te.Pos),
+				End:     pkg.Fset.Position(end),
+				NewText: string(te.NewText),
+			})
+		}
+		f.Fixes = append(f.Fixes, fx)
+	}
+
+	start, stop := f.Posn.Offset, f.End.Offset
+	if stop <= start {
+		// Without a range, use the rest of the line.
+		stop = start
+		for stop < len(content) && content[stop] != '\n' {
+			stop++
+		}
+	}
+	if start <= stop && stop <= len(content) {
+		f.Text = strings.Join(strings.Fields(string(content[start:stop])), " ")
+	}
+	return f
+}
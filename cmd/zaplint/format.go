@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// formats are the output formats of the driver, keyed by the name given
+// with -format.
+var formats = map[string]func(w io.Writer, r *report) error{
+	"text":        writeText,
+	"sarif":       writeSARIF,
+	"checkstyle":  writeCheckstyle,
+	"junit":       writeJUnit,
+	"codeclimate": writeCodeClimate,
+	"html":        writeHTML,
+}
+
+func formatNames() []string {
+	var names []string
+	for name := range formats {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// rule describes a rule of zaplint for the formats that list them.
+type rule struct {
+	Name        string
+	Description string
+	Level       string // "error", "warning" or "note", as in SARIF.
+}
+
+// ruleDocs are the rules that zaplint may report, in the order of the README.
+var ruleDocs = []rule{
+	{"capitalized-message", "Log messages should start with a capital letter.", "warning"},
+	{"replace-any", "zap.Any should be replaced with the field constructor of the value's type.", "warning"},
+	{"key-naming-convention", "Log keys should follow the configured naming convention and be passed as constant strings.", "warning"},
+	{"key-type-consistency", "Log keys should be logged with the same type of value across packages.", "warning"},
+	{"key-registry", "Log keys should be registered in the key registry and logged with the registered constructor and type.", "warning"},
+	{"near-duplicate-keys", "Log keys should not be near-duplicates of other keys, such as user_id and userId.", "warning"},
+	{"directive", "zaplint directives should be well-formed, explained and used.", "note"},
+}
+
+// ruleDoc returns the description of the rule named name.
+func ruleDoc(name string) rule {
+	for _, r := range ruleDocs {
+		if r.Name == name {
+			return r
+		}
+	}
+	return rule{Name: name, Level: "warning"}
+}
+
+// report is what the formats render: the findings of a run, along with
+// what is needed to present their positions.
+type report struct {
+	root     string // The absolute directory that paths are relative to.
+	url      string // The documentation of the analyzer, which rule URLs are relative to.
+	findings []finding
+	files    map[string][]byte // The content of the reported files, read lazily.
+}
+
+// path returns filename relative to the root of r with forward slashes, or
+// as is if it lies outside of the root.
+func (r *report) path(filename string) string {
+	if rel, ok := r.relPath(filename); ok {
+		return rel
+	}
+	return filepath.ToSlash(filename)
+}
+
+// relPath returns filename relative to the root of r with forward slashes,
+// and whether it lies below the root.
+func (r *report) relPath(filename string) (string, bool) {
+	rel, err := filepath.Rel(r.root, filename)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// helpURI returns the documentation of the rule named name.
+func (r *report) helpURI(name string) string {
+	base, err := url.Parse(r.url)
+	if err != nil || r.url == "" {
+		return ""
+	}
+	return base.ResolveReference(&url.URL{Fragment: name}).String()
+}
+
+// source returns the content of filename, or nil if it cannot be read.
+func (r *report) source(filename string) []byte {
+	if r.files == nil {
+		r.files = make(map[string][]byte)
+	}
+	content, ok := r.files[filename]
+	if !ok {
+		content, _ = os.ReadFile(filename)
+		r.files[filename] = content
+	}
+	return content
+}
+
+// column returns the 1-based column of posn in characters rather than
+// bytes, falling back to the byte column if the source is unavailable.
+func (r *report) column(posn token.Position) int {
+	content := r.source(posn.Filename)
+	lineStart := posn.Offset - (posn.Column - 1)
+	if lineStart < 0 || posn.Offset > len(content) {
+		return posn.Column
+	}
+	return utf8.RuneCount(content[lineStart:posn.Offset]) + 1
+}
+
+func writeText(w io.Writer, r *report) error {
+	for _, f := range r.findings {
+		if _, err := fmt.Fprintf(w, "%s: %s\n", f.Posn, f.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
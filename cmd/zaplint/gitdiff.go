@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// lineRange is an inclusive range of line numbers.
+type lineRange struct {
+	start, end int
+}
+
+// changes records the lines changed since a revision, keyed by absolute
+// file path. A nil slice means that the whole file is new.
+type changes map[string][]lineRange
+
+// contains reports whether any line from start to end of filename changed.
+func (c changes) contains(filename string, start, end int) bool {
+	ranges, ok := c[filename]
+	if !ok {
+		return false
+	}
+	if ranges == nil {
+		return true
+	}
+	for _, r := range ranges {
+		if start <= r.end && r.start <= end {
+			return true
+		}
+	}
+	return false
+}
+
+// changedLines returns the lines of the git work tree containing dir that
+// changed since rev, including uncommitted changes and untracked files.
+func changedLines(dir, rev string) (changes, error) {
+	root, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	root = strings.TrimSpace(root)
+
+	// The prefixes are given explicitly, since diff.noprefix and
+	// diff.mnemonicPrefix change them.
+	diff, err := git(root, "-c", "core.quotePath=off", "diff", "--no-color", "--no-ext-diff",
+		"--src-prefix=a/", "--dst-prefix=b/", "-U0", rev, "--")
+	if err != nil {
+		return nil, err
+	}
+	c, err := parseDiff(root, diff)
+	if err != nil {
+		return nil, err
+	}
+
+	untracked, err := git(root, "-c", "core.quotePath=off", "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range strings.Split(strings.TrimSpace(untracked), "\n") {
+		if name != "" {
+			c[filepath.Join(root, name)] = nil
+		}
+	}
+	return c, nil
+}
+
+// parseDiff returns the lines added by a unified diff with paths relative
+// to root, prefixed with "b/".
+func parseDiff(root, diff string) (changes, error) {
+	c := make(changes)
+	var file string
+	scanner := bufio.NewScanner(strings.NewReader(diff))
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			// Git ends names that contain spaces with a tab.
+			name := strings.TrimSuffix(strings.TrimPrefix(line, "+++ "), "\t")
+			if unquoted, err := strconv.Unquote(name); err == nil {
+				name = unquoted
+			}
+			file = ""
+			if name != "/dev/null" {
+				file = filepath.Join(root, strings.TrimPrefix(name, "b/"))
+				c[file] = []lineRange{}
+			}
+		case strings.HasPrefix(line, "@@ ") && file != "":
+			r, err := parseHunk(line)
+			if err != nil {
+				return nil, err
+			}
+			if r.end >= r.start {
+				c[file] = append(c[file], r)
+			}
+		}
+	}
+	return c, scanner.Err()
+}
+
+// parseHunk returns the lines of the new file covered by a hunk header such
+// as "@@ -1,2 +3,4 @@". The range is empty if the hunk only removes lines.
+func parseHunk(header string) (lineRange, error) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return lineRange{}, fmt.Errorf("zaplint: malformed hunk header %q", header)
+	}
+
+	start, count, found := strings.Cut(strings.TrimPrefix(fields[2], "+"), ",")
+	if !found {
+		count = "1"
+	}
+	s, err := strconv.Atoi(start)
+	if err != nil {
+		return lineRange{}, fmt.Errorf("zaplint: malformed hunk header %q", header)
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil {
+		return lineRange{}, fmt.Errorf("zaplint: malformed hunk header %q", header)
+	}
+	return lineRange{start: s, end: s + n - 1}, nil
+}
+
+func git(dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("zaplint: git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestParseDiff(t *testing.T) {
+	t.Parallel()
+	diff := `diff --git a/a.go b/a.go
+--- a/a.go
++++ b/a.go
+@@ -3 +3 @@ func f() {
+-	old()
++	changed()
+@@ -10,2 +9,0 @@ func g() {
+@@ -20,0 +19,3 @@ func h() {
++	one()
++	two()
++	three()
+diff --git a/gone.go b/gone.go
+--- a/gone.go
++++ /dev/null
+@@ -1,3 +0,0 @@
+diff --git "a/sp\303\251cial.go" "b/sp\303\251cial.go"
+--- "a/sp\303\251cial.go"
++++ "b/sp\303\251cial.go"
+@@ -1,0 +2,2 @@
+`
+	c, err := parseDiff("/repo", diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := changes{
+		filepath.FromSlash("/repo/a.go"):       {{3, 3}, {19, 21}},
+		filepath.FromSlash("/repo/spécial.go"): {{2, 3}},
+	}
+	if len(c) != len(want) {
+		t.Fatalf("got %v, want %v", c, want)
+	}
+	for name, ranges := range want {
+		if !slices.Equal(c[name], ranges) {
+			t.Errorf("%s: got %v, want %v", name, c[name], ranges)
+		}
+	}
+
+	for _, tt := range []struct {
+		start, end int
+		want       bool
+	}{
+		{3, 3, true},
+		{4, 18, false},
+		{1, 3, true},
+		{21, 25, true},
+		{22, 22, false},
+	} {
+		if got := c.contains(filepath.FromSlash("/repo/a.go"), tt.start, tt.end); got != tt.want {
+			t.Errorf("contains(%d, %d) = %v, want %v", tt.start, tt.end, got, tt.want)
+		}
+	}
+}
+
+func TestParseHunk(t *testing.T) {
+	t.Parallel()
+	for _, header := range []string{"@@", "@@ -1 1 @@", "@@ -1 +x @@", "@@ -1 +1,y @@"} {
+		if _, err := parseHunk(header); err == nil {
+			t.Errorf("parseHunk(%q) succeeded", header)
+		}
+	}
+}
+
+func TestChangedLines(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := func(args ...string) {
+		t.Helper()
+		args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+		if _, err := git(dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q")
+	if err := os.Mkdir(filepath.Join(dir, "b"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	write("a.go", "1\n2\n3\n4\n")
+	write(filepath.Join("b", "c.go"), "1\n2\n")
+	write("with space.go", "1\n2\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "initial")
+	write("a.go", "1\ntwo\n3\n4\nfive\n")
+	write(filepath.Join("b", "c.go"), "one\n2\n")
+	write("with space.go", "1\ntwo\n")
+	write("b.go", "1\n")
+
+	// The paths do not depend on the prefixes configured for git diff.
+	for _, config := range []string{"", "diff.noprefix", "diff.mnemonicPrefix"} {
+		if config != "" {
+			run("config", config, "true")
+		}
+		c, err := changedLines(dir, "HEAD")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := c[filepath.Join(dir, "a.go")], []lineRange{{2, 2}, {5, 5}}; !slices.Equal(got, want) {
+			t.Errorf("%s: a.go: got %v, want %v", config, got, want)
+		}
+		if got, want := c[filepath.Join(dir, "b", "c.go")], []lineRange{{1, 1}}; !slices.Equal(got, want) {
+			t.Errorf("%s: b/c.go: got %v, want %v", config, got, want)
+		}
+		if got, want := c[filepath.Join(dir, "with space.go")], []lineRange{{2, 2}}; !slices.Equal(got, want) {
+			t.Errorf("%s: with space.go: got %v, want %v", config, got, want)
+		}
+		if !c.contains(filepath.Join(dir, "b.go"), 1, 1) {
+			t.Errorf("%s: untracked b.go should be changed", config)
+		}
+		if config != "" {
+			run("config", "--unset", config)
+		}
+	}
+	if _, err := changedLines(dir, "no-such-rev"); err == nil {
+		t.Errorf("changedLines succeeded with an unknown revision")
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// snippetContext is the number of lines shown around each finding.
+const snippetContext = 2
+
+//go:embed report.html.tmpl
+var htmlTemplateText string
+
+var htmlTemplate = template.Must(template.New("report").Parse(htmlTemplateText))
+
+type htmlReport struct {
+	Total int
+	Rules []*htmlRule
+}
+
+type htmlRule struct {
+	rule
+	HelpURI  string
+	Count    int
+	Packages []*htmlPackage
+}
+
+type htmlPackage struct {
+	Path  string
+	Count int
+	Files []*htmlFile
+}
+
+type htmlFile struct {
+	Path     string
+	Findings []htmlFinding
+}
+
+type htmlFinding struct {
+	Line, Column int
+	Message      string
+	Snippet      []htmlLine
+	Fixes        []htmlFix
+}
+
+type htmlLine struct {
+	Number    int
+	Op        string // "-" or "+" for the lines of a diff, empty otherwise.
+	Text      string
+	Highlight bool
+}
+
+type htmlFix struct {
+	Message string
+	Files   []htmlDiff
+}
+
+type htmlDiff struct {
+	Path  string
+	Lines []htmlLine
+}
+
+// writeHTML writes the findings of r as a self-contained HTML page that
+// groups them by rule, package and file, with the source around each
+// finding and its suggested fixes as diffs.
+func writeHTML(w io.Writer, r *report) error {
+	out := &htmlReport{Total: len(r.findings)}
+	rules := make(map[string]*htmlRule)
+	for _, doc := range ruleDocs {
+		rules[doc.Name] = &htmlRule{rule: doc, HelpURI: r.helpURI(doc.Name)}
+		out.Rules = append(out.Rules, rules[doc.Name])
+	}
+
+	for _, f := range r.findings {
+		hr, ok := rules[f.Category]
+		if !ok {
+			hr = &htmlRule{rule: ruleDoc(f.Category)}
+			rules[f.Category] = hr
+			out.Rules = append(out.Rules, hr)
+		}
+		hr.Count++
+
+		i := slices.IndexFunc(hr.Packages, func(p *htmlPackage) bool { return p.Path == f.Package })
+		if i < 0 {
+			i = len(hr.Packages)
+			hr.Packages = append(hr.Packages, &htmlPackage{Path: f.Package})
+		}
+		pkg := hr.Packages[i]
+		pkg.Count++
+
+		path := r.path(f.Posn.Filename)
+		i = slices.IndexFunc(pkg.Files, func(file *htmlFile) bool { return file.Path == path })
+		if i < 0 {
+			i = len(pkg.Files)
+			pkg.Files = append(pkg.Files, &htmlFile{Path: path})
+		}
+
+		hf := htmlFinding{
+			Line:    f.Posn.Line,
+			Column:  r.column(f.Posn),
+			Message: f.Message,
+			Snippet: r.snippet(f),
+		}
+		for _, fx := range f.Fixes {
+			hf.Fixes = append(hf.Fixes, r.htmlFix(fx))
+		}
+		pkg.Files[i].Findings = append(pkg.Files[i].Findings, hf)
+	}
+
+	for _, hr := range out.Rules {
+		slices.SortFunc(hr.Packages, func(a, b *htmlPackage) int { return strings.Compare(a.Path, b.Path) })
+	}
+	return htmlTemplate.Execute(w, out)
+}
+
+// writeHTMLFile writes the HTML report of r to path.
+func writeHTMLFile(path string, r *report) error {
+	var buf bytes.Buffer
+	if err := writeHTML(&buf, r); err != nil {
+		return fmt.Errorf("zaplint: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("zaplint: %w", err)
+	}
+	return nil
+}
+
+// snippet returns the lines of source around f, highlighting those it
+// reports.
+func (r *report) snippet(f finding) []htmlLine {
+	lines := strings.Split(string(r.source(f.Posn.Filename)), "\n")
+	first := max(f.Posn.Line-snippetContext, 1)
+	last := min(f.End.Line+snippetContext, len(lines))
+	var snippet []htmlLine
+	for n := first; n <= last; n++ {
+		snippet = append(snippet, htmlLine{
+			Number:    n,
+			Text:      lines[n-1],
+			Highlight: f.Posn.Line <= n && n <= f.End.Line,
+		})
+	}
+	return snippet
+}
+
+// htmlFix renders fx as a diff per file of the lines it edits.
+func (r *report) htmlFix(fx fix) htmlFix {
+	hf := htmlFix{Message: fx.Message}
+	var files []string
+	edits := make(map[string][]edit)
+	for _, e := range fx.Edits {
+		if _, ok := edits[e.Start.Filename]; !ok {
+			files = append(files, e.Start.Filename)
+		}
+		edits[e.Start.Filename] = append(edits[e.Start.Filename], e)
+	}
+
+	for _, filename := range files {
+		content := r.source(filename)
+		es := edits[filename]
+		slices.SortFunc(es, func(a, b edit) int { return a.Start.Offset - b.Start.Offset })
+
+		// Expand the edited range to whole lines.
+		start, end := es[0].Start.Offset, es[len(es)-1].End.Offset
+		if start < 0 || end > len(content) || start > end {
+			continue
+		}
+		for start > 0 && content[start-1] != '\n' {
+			start--
+		}
+		for end < len(content) && content[end] != '\n' {
+			end++
+		}
+
+		var edited strings.Builder
+		offset := start
+		for _, e := range es {
+			if e.Start.Offset < offset {
+				continue // overlapping edits cannot be applied
+			}
+			edited.Write(content[offset:e.Start.Offset])
+			edited.WriteString(e.NewText)
+			offset = e.End.Offset
+		}
+		edited.Write(content[offset:end])
+
+		diff := htmlDiff{Path: r.path(filename)}
+		if before := string(content[start:end]); before != "" || edited.Len() == 0 {
+			for _, line := range strings.Split(before, "\n") {
+				diff.Lines = append(diff.Lines, htmlLine{Op: "-", Text: line})
+			}
+		}
+		if after := edited.String(); after != "" {
+			for _, line := range strings.Split(after, "\n") {
+				diff.Lines = append(diff.Lines, htmlLine{Op: "+", Text: line})
+			}
+		}
+		hf.Files = append(hf.Files, diff)
+	}
+	return hf
+}
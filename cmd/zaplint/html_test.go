@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHTML(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "report.html")
+
+	var stdout, stderr bytes.Buffer
+	d := &driver{dir: testdata}
+	if code := d.run([]string{"-html", path, "-replace-any", "true", "./replace_any"}, &stdout, &stderr); code != 3 {
+		t.Fatalf("exit code %d: %s", code, stderr.String())
+	}
+	if stdout.Len() == 0 {
+		t.Fatal("findings should still be printed")
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := string(content)
+	for _, want := range []string{
+		`<section id="rule-replace-any">`,
+		`<h3 style="display: inline">src/replace_any (`,
+		`<h4 style="display: inline">replace_any/replace_any.go</h4>`,
+		`<span class="message">replace zap.Any with zap.String</span>`,
+		`<span class="line highlight"><span class="number">16</span>`,
+		`Suggested fix: Replace zap.Any with zap.String`,
+		`<span class="line add">&#43; 	logger.Info(&#34;message&#34;, zap.String(&#34;user_name&#34;`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("report does not contain %s", want)
+		}
+	}
+	if strings.Contains(page, `id="rule-capitalized-message"`) {
+		t.Error("report contains a section for a rule without findings")
+	}
+}
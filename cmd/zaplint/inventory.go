@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/rleungx/zaplint"
+)
+
+// record is a log call of the inventory, as written by "zaplint inventory".
+type record struct {
+	Package  string        `json:"package"`
+	File     string        `json:"file"`
+	Line     int           `json:"line"`
+	Column   int           `json:"column"`
+	Function string        `json:"function,omitempty"`
+	Logger   string        `json:"logger"`
+	Method   string        `json:"method"`
+	Level    string        `json:"level,omitempty"`
+	Message  *string       `json:"message"` // nil if the message is not a constant.
+	Fields   []recordField `json:"fields"`
+}
+
+type recordField struct {
+	Key         string `json:"key"`
+	Constructor string `json:"constructor,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Encoding    string `json:"encoding,omitempty"`
+}
+
+// runInventory implements "zaplint inventory", which writes the log calls
+// of the packages matching the arguments as a JSON array.
+func (d *driver) runInventory(args []string, stdout, stderr io.Writer) int {
+	fset := flag.NewFlagSet("zaplint inventory", flag.ContinueOnError)
+	fset.SetOutput(stderr)
+	fset.Usage = func() {
+		fmt.Fprintln(fset.Output(), "usage: zaplint inventory [flags] packages...")
+		fset.PrintDefaults()
+	}
+	fset.BoolVar(&d.tests, "test", false, "indicates whether test files should be included, too")
+	if err := fset.Parse(args); err != nil {
+		return 1
+	}
+
+	records, err := d.inventory(fset.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(records); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// inventory returns the log calls of the packages matching patterns,
+// ordered by position, with paths relative to the directory the packages
+// were loaded from.
+func (d *driver) inventory(patterns []string) ([]record, error) {
+	graph, err := d.load(zaplint.Inventory, patterns)
+	if err != nil {
+		return nil, err
+	}
+	root, err := d.root()
+	if err != nil {
+		return nil, err
+	}
+	r := &report{root: root}
+
+	records := []record{}
+	seen := make(map[string]bool)
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			return nil, act.Err
+		}
+		for _, call := range act.Result.([]*zaplint.LogCall) {
+			posn := act.Package.Fset.Position(call.Pos)
+			// Packages and their test variants share files.
+			if seen[posn.String()] {
+				continue
+			}
+			seen[posn.String()] = true
+
+			rec := record{
+				Package:  act.Package.PkgPath,
+				File:     r.path(posn.Filename),
+				Line:     posn.Line,
+				Column:   r.column(posn),
+				Function: call.Function,
+				Logger:   call.Kind,
+				Method:   call.Method,
+				Level:    call.Level,
+				Fields:   []recordField{},
+			}
+			if call.ConstantMessage {
+				rec.Message = &call.Message
+			}
+			for _, f := range call.Fields {
+				rec.Fields = append(rec.Fields, recordField{Key: f.Key, Constructor: f.Constructor, Type: f.Type, Encoding: f.Encoding})
+			}
+			records = append(records, rec)
+		}
+	}
+
+	slices.SortFunc(records, func(a, b record) int {
+		if c := strings.Compare(a.File, b.File); c != 0 {
+			return c
+		}
+		if c := a.Line - b.Line; c != 0 {
+			return c
+		}
+		return a.Column - b.Column
+	})
+	return records, nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestInventory(t *testing.T) {
+	t.Parallel()
+	var stdout, stderr bytes.Buffer
+	d := &driver{dir: testdata}
+	if code := d.runInventory([]string{"./inventory"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr.String())
+	}
+
+	var records []record
+	if err := json.Unmarshal(stdout.Bytes(), &records); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 9 {
+		t.Fatalf("got %d records, want 9", len(records))
+	}
+
+	first := records[0]
+	if first.Package != "src/inventory" || first.File != "inventory/inventory.go" || first.Line != 19 || first.Column != 2 ||
+		first.Function != "(*Server).Start" || first.Logger != "Logger" || first.Level != "info" || first.Message == nil || *first.Message != "Server started" {
+		t.Fatalf("unexpected record %+v", first)
+	}
+	if len(first.Fields) != 2 || first.Fields[1] != (recordField{Key: "timeout", Constructor: "zap.Duration", Type: "time.Duration", Encoding: "zap.Duration"}) {
+		t.Fatalf("unexpected fields %+v", first.Fields)
+	}
+
+	// The message of logger.Debug(reason) is not a constant.
+	if records[5].Method != "Debug" || records[5].Message != nil {
+		t.Fatalf("unexpected record %+v", records[5])
+	}
+}
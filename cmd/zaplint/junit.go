@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string       `xml:"name,attr"`
+	ClassName string       `xml:"classname,attr"`
+	File      string       `xml:"file,attr,omitempty"`
+	Line      int          `xml:"line,attr,omitempty"`
+	Failure   junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes the findings of r as a JUnit XML report, with a test
+// suite per package and a failed test case per finding.
+func writeJUnit(w io.Writer, r *report) error {
+	out := junitTestSuites{Name: "zaplint"}
+	index := make(map[string]int)
+	for _, f := range r.findings {
+		i, ok := index[f.Package]
+		if !ok {
+			i = len(out.Suites)
+			index[f.Package] = i
+			out.Suites = append(out.Suites, junitTestSuite{Name: f.Package})
+		}
+
+		name := r.path(f.Posn.Filename)
+		posn := fmt.Sprintf("%s:%d:%d", name, f.Posn.Line, r.column(f.Posn))
+		suite := &out.Suites[i]
+		suite.Cases = append(suite.Cases, junitTestCase{
+			Name:      f.Category + ": " + posn,
+			ClassName: f.Package,
+			File:      name,
+			Line:      f.Posn.Line,
+			Failure: junitFailure{
+				Message: f.Message,
+				Type:    f.Category,
+				Text:    posn + ": " + f.Message,
+			},
+		})
+		suite.Tests++
+		suite.Failures++
+		out.Tests++
+		out.Failures++
+	}
+	return writeXML(w, out)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestJUnit(t *testing.T) {
+	t.Parallel()
+	var stdout, stderr bytes.Buffer
+	d := &driver{dir: testdata}
+	args := []string{"-format", "junit", "-capitalized-message", "true", "-key-naming-convention", "snake", "./capitalized_message", "./sugared_logger"}
+	if code := d.run(args, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr.String())
+	}
+
+	var out junitTestSuites
+	if err := xml.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Suites) != 2 || out.Suites[0].Name != "src/capitalized_message" || out.Suites[1].Name != "src/sugared_logger" {
+		t.Fatalf("unexpected suites %+v", out.Suites)
+	}
+
+	total := 0
+	for _, suite := range out.Suites {
+		if suite.Tests != len(suite.Cases) || suite.Failures != suite.Tests {
+			t.Fatalf("suite %s: tests=%d failures=%d cases=%d", suite.Name, suite.Tests, suite.Failures, len(suite.Cases))
+		}
+		for _, c := range suite.Cases {
+			if c.ClassName != suite.Name || !strings.HasPrefix(c.Name, c.Failure.Type+": ") || c.Failure.Message == "" {
+				t.Fatalf("unexpected test case %+v", c)
+			}
+		}
+		total += suite.Tests
+	}
+	if out.Tests != total || out.Failures != total {
+		t.Fatalf("tests=%d failures=%d, want %d", out.Tests, out.Failures, total)
+	}
+}
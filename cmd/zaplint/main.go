@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"runtime"
 
@@ -12,7 +13,28 @@ import (
 
 var version = "dev" // injected at build time.
 
+// commands are the subcommands of zaplint, keyed by name.
+var commands = map[string]func(d *driver, args []string, stdout, stderr io.Writer) int{
+	"catalog":    (*driver).runCatalog,
+	"check-refs": (*driver).runCheckRefs,
+	"diff-logs":  (*driver).runDiffLogs,
+	"inventory":  (*driver).runInventory,
+	"schema":     (*driver).runSchema,
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			d := &driver{}
+			os.Exit(command(d, os.Args[2:], os.Stdout, os.Stderr))
+		}
+	}
+
+	if usesDriver(os.Args[1:]) {
+		d := &driver{}
+		os.Exit(d.run(os.Args[1:], os.Stdout, os.Stderr))
+	}
+
 	// override the builtin -V flag.
 	flag.Var(versionFlag{}, "V", "print version and exit")
 	singlechecker.Main(zaplint.New(nil))
@@ -23,7 +45,11 @@ type versionFlag struct{}
 func (versionFlag) String() string   { return "" }
 func (versionFlag) IsBoolFlag() bool { return true }
 func (versionFlag) Set(string) error {
-	fmt.Printf("zaplint version %s %s/%s\n", version, runtime.GOOS, runtime.GOARCH)
+	fmt.Println(versionString())
 	os.Exit(0)
 	return nil
 }
+
+func versionString() string {
+	return fmt.Sprintf("zaplint version %s %s/%s", version, runtime.GOOS, runtime.GOARCH)
+}
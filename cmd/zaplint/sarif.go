@@ -0,0 +1,211 @@
+package main
+
+import (
+	"encoding/json"
+	"go/token"
+	"io"
+	"net/url"
+	"path"
+	"path/filepath"
+)
+
+// The subset of SARIF 2.1.0 that zaplint produces; see
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifSrcRoot = "%SRCROOT%"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                        `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	ColumnKind         string                           `json:"columnKind"`
+	Results            []sarifResult                    `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	HelpURI              string             `json:"helpUri,omitempty"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+	Fixes     []sarifFix      `json:"fixes,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion  `json:"deletedRegion"`
+	InsertedContent sarifMessage `json:"insertedContent"`
+}
+
+// writeSARIF writes the findings of r as a SARIF log with a single run.
+// Paths below the root of r are relative to %SRCROOT%, so that logs of
+// different checkouts can be compared.
+func writeSARIF(w io.Writer, r *report) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "zaplint",
+			Version:        version,
+			InformationURI: "https://github.com/rleungx/zaplint",
+			Rules:          []sarifRule{},
+		}},
+		OriginalURIBaseIDs: map[string]sarifArtifactLocation{
+			sarifSrcRoot: {URI: fileURI(r.root) + "/"},
+		},
+		ColumnKind: "unicodeCodePoints",
+		Results:    []sarifResult{},
+	}
+
+	ruleIndex := make(map[string]int)
+	for _, doc := range ruleDocs {
+		ruleIndex[doc.Name] = len(run.Tool.Driver.Rules)
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:                   doc.Name,
+			ShortDescription:     sarifMessage{Text: doc.Description},
+			HelpURI:              r.helpURI(doc.Name),
+			DefaultConfiguration: sarifConfiguration{Level: doc.Level},
+		})
+	}
+
+	for _, f := range r.findings {
+		index, ok := ruleIndex[f.Category]
+		if !ok {
+			index = len(run.Tool.Driver.Rules)
+			ruleIndex[f.Category] = index
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:                   f.Category,
+				DefaultConfiguration: sarifConfiguration{Level: "warning"},
+			})
+		}
+
+		result := sarifResult{
+			RuleID:    f.Category,
+			RuleIndex: index,
+			Level:     ruleDoc(f.Category).Level,
+			Message:   sarifMessage{Text: f.Message},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: r.sarifArtifact(f.Posn.Filename),
+				Region:           r.sarifRegion(f.Posn, f.End),
+			}}},
+		}
+		for _, fx := range f.Fixes {
+			result.Fixes = append(result.Fixes, r.sarifFix(fx))
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{Version: sarifVersion, Schema: sarifSchema, Runs: []sarifRun{run}})
+}
+
+// sarifFix converts fx, grouping its edits by file.
+func (r *report) sarifFix(fx fix) sarifFix {
+	sf := sarifFix{Description: sarifMessage{Text: fx.Message}}
+	changes := make(map[string]int)
+	for _, e := range fx.Edits {
+		i, ok := changes[e.Start.Filename]
+		if !ok {
+			i = len(sf.ArtifactChanges)
+			changes[e.Start.Filename] = i
+			sf.ArtifactChanges = append(sf.ArtifactChanges, sarifArtifactChange{
+				ArtifactLocation: r.sarifArtifact(e.Start.Filename),
+			})
+		}
+		sf.ArtifactChanges[i].Replacements = append(sf.ArtifactChanges[i].Replacements, sarifReplacement{
+			DeletedRegion:   r.sarifRegion(e.Start, e.End),
+			InsertedContent: sarifMessage{Text: e.NewText},
+		})
+	}
+	return sf
+}
+
+func (r *report) sarifArtifact(filename string) sarifArtifactLocation {
+	rel, ok := r.relPath(filename)
+	if !ok {
+		return sarifArtifactLocation{URI: fileURI(filename)}
+	}
+	return sarifArtifactLocation{URI: (&url.URL{Path: rel}).String(), URIBaseID: sarifSrcRoot}
+}
+
+func (r *report) sarifRegion(start, end token.Position) sarifRegion {
+	return sarifRegion{
+		StartLine:   start.Line,
+		StartColumn: r.column(start),
+		EndLine:     end.Line,
+		EndColumn:   r.column(end),
+	}
+}
+
+// fileURI returns the file URI of the absolute path filename.
+func fileURI(filename string) string {
+	p := filepath.ToSlash(filename)
+	if !path.IsAbs(p) {
+		// Windows paths such as C:/dir need a leading slash.
+		p = "/" + p
+	}
+	return (&url.URL{Scheme: "file", Path: p}).String()
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestSARIF(t *testing.T) {
+	t.Parallel()
+	var stdout, stderr bytes.Buffer
+	d := &driver{dir: testdata}
+	if code := d.run([]string{"-format", "sarif", "-replace-any", "true", "./replace_any"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr.String())
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(stdout.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != sarifVersion || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log %+v", log)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != len(ruleDocs) {
+		t.Fatalf("got %d rules, want %d", len(run.Tool.Driver.Rules), len(ruleDocs))
+	}
+	if len(run.Results) == 0 {
+		t.Fatal("no results")
+	}
+
+	for _, result := range run.Results {
+		rule := run.Tool.Driver.Rules[result.RuleIndex]
+		if result.RuleID != "replace-any" || rule.ID != result.RuleID || rule.HelpURI != "https://github.com/rleungx/zaplint#replace-any" {
+			t.Fatalf("unexpected rule %+v of result %+v", rule, result)
+		}
+		loc := result.Locations[0].PhysicalLocation
+		if loc.ArtifactLocation.URIBaseID != sarifSrcRoot || loc.ArtifactLocation.URI[0] == '/' || loc.Region.StartLine == 0 {
+			t.Fatalf("unexpected location %+v", loc)
+		}
+	}
+
+	// zap.Any with a string is replaced by zap.String.
+	result := run.Results[0]
+	if result.Message.Text != "replace zap.Any with zap.String" || len(result.Fixes) != 1 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	replacement := result.Fixes[0].ArtifactChanges[0].Replacements[0]
+	region := replacement.DeletedRegion
+	if replacement.InsertedContent.Text != "String" || region.StartLine != region.EndLine || region.EndColumn-region.StartColumn != len("Any") {
+		t.Fatalf("unexpected replacement %+v", replacement)
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	t.Parallel()
+	var stdout, stderr bytes.Buffer
+	d := &driver{dir: testdata}
+	if code := d.run([]string{"-format", "xml", "./replace_any"}, &stdout, &stderr); code != 1 {
+		t.Fatalf("exit code %d, want 1", code)
+	}
+	if !bytes.Contains(stderr.Bytes(), []byte("unknown format")) {
+		t.Fatalf("unexpected error: %s", stderr.String())
+	}
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonSchema is the subset of JSON Schema that "zaplint schema" produces.
+type jsonSchema struct {
+	Schema      string                 `json:"$schema,omitempty"`
+	Ref         string                 `json:"$ref,omitempty"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Type        any                    `json:"type,omitempty"` // A type name, or a list of them.
+	Format      string                 `json:"format,omitempty"`
+	Const       *string                `json:"const,omitempty"`
+	Enum        []string               `json:"enum,omitempty"`
+	Properties  map[string]*jsonSchema `json:"properties,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	Items       *jsonSchema            `json:"items,omitempty"`
+	AnyOf       []*jsonSchema          `json:"anyOf,omitempty"`
+	Defs        map[string]*jsonSchema `json:"$defs,omitempty"`
+}
+
+// encoderConfig mirrors the settings of zapcore.EncoderConfig that the
+// schema of log entries depends on.
+type encoderConfig struct {
+	messageKey      string
+	levelKey        string
+	durationEncoder string // As in zapcore.DurationEncoder.UnmarshalText.
+	timeEncoder     string // As in zapcore.TimeEncoder.UnmarshalText.
+}
+
+var (
+	durationEncoders = []string{"seconds", "nanos", "ms", "string"}
+	timeEncoders     = []string{"epoch", "millis", "nanos", "iso8601", "rfc3339", "rfc3339nano"}
+)
+
+// runSchema implements "zaplint schema", which writes a JSON Schema of the
+// log entries that the packages matching the arguments emit through zap's
+// JSON encoder.
+func (d *driver) runSchema(args []string, stdout, stderr io.Writer) int {
+	fset := flag.NewFlagSet("zaplint schema", flag.ContinueOnError)
+	fset.SetOutput(stderr)
+	fset.Usage = func() {
+		fmt.Fprintln(fset.Output(), "usage: zaplint schema [flags] packages...")
+		fset.PrintDefaults()
+	}
+	var cfg encoderConfig
+	fset.StringVar(&cfg.messageKey, "message-key", "msg", "the key of the message in log entries")
+	fset.StringVar(&cfg.levelKey, "level-key", "level", "the key of the level in log entries")
+	fset.StringVar(&cfg.durationEncoder, "duration-encoder", "seconds", "how durations are encoded: "+strings.Join(durationEncoders, ", "))
+	fset.StringVar(&cfg.timeEncoder, "time-encoder", "epoch", "how times are encoded: "+strings.Join(timeEncoders, ", "))
+	fset.BoolVar(&d.tests, "test", false, "indicates whether log calls in test files should be included, too")
+	if err := fset.Parse(args); err != nil {
+		return 1
+	}
+	if !slices.Contains(durationEncoders, cfg.durationEncoder) {
+		fmt.Fprintf(stderr, "zaplint: -duration-encoder=%s: unknown encoder (want one of %s)\n", cfg.durationEncoder, strings.Join(durationEncoders, ", "))
+		return 1
+	}
+	if !slices.Contains(timeEncoders, strings.ToLower(cfg.timeEncoder)) {
+		fmt.Fprintf(stderr, "zaplint: -time-encoder=%s: unknown encoder (want one of %s)\n", cfg.timeEncoder, strings.Join(timeEncoders, ", "))
+		return 1
+	}
+
+	records, err := d.inventory(fset.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(logSchema(records, cfg)); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// logSchema returns a schema with a definition per constant message of
+// records, and the union of those as the schema of any log entry. Entries
+// may have more keys than the schema lists, such as the timestamp, the
+// caller or the fields added to the logger with With.
+func logSchema(records []record, cfg encoderConfig) *jsonSchema {
+	// The messages of printf-style methods are format strings rather than
+	// the messages logged.
+	records = slices.DeleteFunc(slices.Clone(records), func(r record) bool {
+		return r.Logger == "SugaredLogger" && strings.HasSuffix(r.Method, "f")
+	})
+
+	var texts []string
+	byMessage := make(map[string][]record)
+	for _, r := range records {
+		if r.Message == nil {
+			continue
+		}
+		if _, ok := byMessage[*r.Message]; !ok {
+			texts = append(texts, *r.Message)
+		}
+		byMessage[*r.Message] = append(byMessage[*r.Message], r)
+	}
+	slices.Sort(texts)
+
+	root := &jsonSchema{
+		Schema:      jsonSchemaDraft,
+		Title:       "Log entries",
+		Description: "The structured log entries emitted through zap, as found by zaplint.",
+		Defs:        make(map[string]*jsonSchema),
+		AnyOf:       []*jsonSchema{},
+	}
+	for _, text := range texts {
+		name := defName(text, root.Defs)
+		root.Defs[name] = messageSchema(text, byMessage[text], cfg)
+		root.AnyOf = append(root.AnyOf, &jsonSchema{Ref: "#/$defs/" + name})
+	}
+	return root
+}
+
+// messageSchema returns the schema of the entries with the message text,
+// logged by records.
+func messageSchema(text string, records []record, cfg encoderConfig) *jsonSchema {
+	s := &jsonSchema{
+		Title:      text,
+		Type:       "object",
+		Properties: map[string]*jsonSchema{cfg.messageKey: {Const: &text}},
+		Required:   []string{cfg.levelKey, cfg.messageKey},
+	}
+
+	var (
+		levels    []string
+		dynamic   bool // Whether any record has a level that is not a constant.
+		emitters  []string
+		keys      []string
+		encodings = make(map[string][]string)
+		count     = make(map[string]int) // The number of records with each key.
+	)
+	for _, r := range records {
+		if r.Level == "" {
+			dynamic = true
+		} else if !slices.Contains(levels, r.Level) {
+			levels = append(levels, r.Level)
+		}
+		if emitter := fmt.Sprintf("%s (%s:%d)", catalogEmitter(r), r.File, r.Line); !slices.Contains(emitters, emitter) {
+			emitters = append(emitters, emitter)
+		}
+
+		seen := make(map[string]bool)
+		for _, f := range r.Fields {
+			if f.Key == cfg.messageKey || f.Key == cfg.levelKey {
+				continue
+			}
+			if _, ok := encodings[f.Key]; !ok {
+				keys = append(keys, f.Key)
+			}
+			if !slices.Contains(encodings[f.Key], f.Encoding) {
+				encodings[f.Key] = append(encodings[f.Key], f.Encoding)
+			}
+			if !seen[f.Key] {
+				seen[f.Key] = true
+				count[f.Key]++
+			}
+		}
+	}
+	s.Description = "Logged by " + strings.Join(emitters, ", ") + "."
+
+	level := &jsonSchema{Type: "string"}
+	if !dynamic {
+		slices.Sort(levels)
+		level.Enum = levels
+	}
+	s.Properties[cfg.levelKey] = level
+
+	for _, key := range keys {
+		var alternatives []*jsonSchema
+		seen := make(map[string]bool)
+		for _, encoding := range encodings[key] {
+			vs := valueSchema(encoding, cfg)
+			data, _ := json.Marshal(vs)
+			if !seen[string(data)] {
+				seen[string(data)] = true
+				alternatives = append(alternatives, vs)
+			}
+		}
+		switch {
+		case len(alternatives) == 1:
+			s.Properties[key] = alternatives[0]
+		case slices.ContainsFunc(alternatives, func(vs *jsonSchema) bool { return vs.Type == nil && vs.AnyOf == nil }):
+			// One of the values may be anything.
+			s.Properties[key] = &jsonSchema{}
+		default:
+			s.Properties[key] = &jsonSchema{AnyOf: alternatives}
+		}
+		if count[key] == len(records) {
+			s.Required = append(s.Required, key)
+		}
+	}
+	return s
+}
+
+// valueSchema returns the schema of the values encoded by the zap field
+// constructor encoding, such as "zap.Int64", in zap's JSON encoder.
+func valueSchema(encoding string, cfg encoderConfig) *jsonSchema {
+	name := strings.TrimPrefix(encoding, "zap.")
+	switch name {
+	case "Bool":
+		return &jsonSchema{Type: "boolean"}
+	case "Int", "Int64", "Int32", "Int16", "Int8", "Uint", "Uint64", "Uint32", "Uint16", "Uint8", "Uintptr":
+		return &jsonSchema{Type: "integer"}
+	case "Float64", "Float32":
+		// NaN and infinities are encoded as strings.
+		return &jsonSchema{Type: []string{"number", "string"}}
+	case "String", "ByteString", "Stringer", "Complex128", "Complex64", "NamedError", "Error", "Stack", "StackSkip":
+		return &jsonSchema{Type: "string"}
+	case "Binary":
+		return &jsonSchema{Type: "string", Format: "base64"}
+	case "Duration":
+		switch cfg.durationEncoder {
+		case "string":
+			return &jsonSchema{Type: "string"}
+		case "nanos", "ms":
+			return &jsonSchema{Type: "integer"}
+		default:
+			return &jsonSchema{Type: "number"}
+		}
+	case "Time":
+		switch strings.ToLower(cfg.timeEncoder) {
+		case "iso8601":
+			return &jsonSchema{Type: "string"}
+		case "rfc3339", "rfc3339nano":
+			return &jsonSchema{Type: "string", Format: "date-time"}
+		case "nanos":
+			return &jsonSchema{Type: "integer"}
+		default:
+			return &jsonSchema{Type: "number"}
+		}
+	case "Object", "Dict", "Namespace", "Inline":
+		return &jsonSchema{Type: "object"}
+	case "Array":
+		return &jsonSchema{Type: "array"}
+	case "Objects", "ObjectValues", "Errors":
+		return &jsonSchema{Type: "array", Items: &jsonSchema{Type: "object"}}
+	}
+
+	// Pointers are encoded as null when nil, and slices as arrays.
+	if elem, ok := strings.CutSuffix(name, "p"); ok {
+		if vs := valueSchema(elem, cfg); vs.Type != nil && vs.Type != "object" {
+			vs.Type = append(schemaTypes(vs.Type), "null")
+			return vs
+		}
+	}
+	if elem, ok := strings.CutSuffix(name, "s"); ok {
+		if vs := valueSchema(elem, cfg); vs.Type != nil {
+			return &jsonSchema{Type: "array", Items: vs}
+		}
+	}
+	// Anything else, such as zap.Reflect, may be encoded as any value.
+	return &jsonSchema{}
+}
+
+func schemaTypes(t any) []string {
+	switch t := t.(type) {
+	case string:
+		return []string{t}
+	case []string:
+		return slices.Clone(t)
+	default:
+		return nil
+	}
+}
+
+// defName returns a name for the definition of the message text that is
+// not yet used in defs, such as "server_started" for "Server started".
+func defName(text string, defs map[string]*jsonSchema) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if b.Len() > 0 {
+			b.WriteByte('_')
+		}
+		b.WriteString(strings.ToLower(word))
+	}
+	base := b.String()
+	if base == "" {
+		base = "message"
+	}
+	name := base
+	for i := 2; defs[name] != nil; i++ {
+		name = base + "_" + strconv.Itoa(i)
+	}
+	return name
+}
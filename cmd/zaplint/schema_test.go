@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+	var stdout, stderr bytes.Buffer
+	d := &driver{dir: testdata}
+	if code := d.runSchema([]string{"-duration-encoder", "string", "./inventory"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr.String())
+	}
+
+	var schema jsonSchema
+	if err := json.Unmarshal(stdout.Bytes(), &schema); err != nil {
+		t.Fatal(err)
+	}
+	// "Stopping %s" is a format string, and the message of
+	// logger.Debug(reason) is not a constant.
+	if len(schema.Defs) != 7 || len(schema.AnyOf) != 7 {
+		t.Fatalf("got %d definitions and %d alternatives, want 7", len(schema.Defs), len(schema.AnyOf))
+	}
+	if _, ok := schema.Defs["stopping_s"]; ok {
+		t.Fatal("unexpected definition of a format string")
+	}
+
+	started := schema.Defs["server_started"]
+	if started == nil || *started.Properties["msg"].Const != "Server started" || !slices.Equal(started.Properties["level"].Enum, []string{"info"}) {
+		t.Fatalf("unexpected definition %+v", started)
+	}
+	if started.Properties["addr"].Type != "string" || started.Properties["timeout"].Type != "string" {
+		t.Fatalf("unexpected properties %+v", started.Properties)
+	}
+	if !slices.Equal(started.Required, []string{"level", "msg", "addr", "timeout"}) {
+		t.Fatalf("unexpected required keys %v", started.Required)
+	}
+
+	// zap.Any and loosely typed values are typed after their Go type.
+	stopped := schema.Defs["server_stopped"]
+	if stopped.Properties["reason"].Type != "string" || stopped.Properties["graceful"].Type != "boolean" || stopped.Properties["code"].Type != "integer" {
+		t.Fatalf("unexpected properties %+v", stopped.Properties)
+	}
+	if schema.Defs["start_failed"].Properties["attempt"].Type != "integer" {
+		t.Fatalf("unexpected properties %+v", schema.Defs["start_failed"].Properties)
+	}
+}
+
+func TestValueSchema(t *testing.T) {
+	t.Parallel()
+	cfg := encoderConfig{durationEncoder: "seconds", timeEncoder: "epoch"}
+	for _, tt := range []struct {
+		encoding string
+		cfg      func(*encoderConfig)
+		want     string
+	}{
+		{"zap.Int64", nil, `{"type":"integer"}`},
+		{"zap.Uint8p", nil, `{"type":["integer","null"]}`},
+		{"zap.Float64s", nil, `{"type":"array","items":{"type":["number","string"]}}`},
+		{"zap.Strings", nil, `{"type":"array","items":{"type":"string"}}`},
+		{"zap.Duration", nil, `{"type":"number"}`},
+		{"zap.Duration", func(c *encoderConfig) { c.durationEncoder = "nanos" }, `{"type":"integer"}`},
+		{"zap.Durations", func(c *encoderConfig) { c.durationEncoder = "string" }, `{"type":"array","items":{"type":"string"}}`},
+		{"zap.Time", nil, `{"type":"number"}`},
+		{"zap.Timep", func(c *encoderConfig) { c.timeEncoder = "RFC3339" }, `{"type":["string","null"],"format":"date-time"}`},
+		{"zap.Errors", nil, `{"type":"array","items":{"type":"object"}}`},
+		{"zap.Reflect", nil, `{}`},
+		{"", nil, `{}`},
+	} {
+		c := cfg
+		if tt.cfg != nil {
+			tt.cfg(&c)
+		}
+		data, err := json.Marshal(valueSchema(tt.encoding, c))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("valueSchema(%q) = %s, want %s", tt.encoding, data, tt.want)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package zaplint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+	"gopkg.in/yaml.v3"
+)
+
+// configFileNames are the names searched for when no configuration file is
+// given explicitly.
+var configFileNames = []string{".zaplint.yaml", ".zaplint.yml"}
+
+// config is the content of a configuration file. Each field mirrors the
+// command line flag of the same name; unset fields are nil.
+//
+// Configuration files cascade: a file applies to the packages in its
+// directory and below, on top of the files found in parent directories,
+// unless it is marked as the root.
+type config struct {
+	Root                     bool     `yaml:"root"` // Stop looking for configuration files in parent directories.
+	CapitalizedMessage       *bool    `yaml:"capitalized-message"`
+	ReplaceAny               *bool    `yaml:"replace-any"`
+	KeyNamingConvention      *string  `yaml:"key-naming-convention"`
+	KeyTypeConsistency       *bool    `yaml:"key-type-consistency"`
+	KeyRegistry              *string  `yaml:"key-registry"` // Relative to the directory of the file.
+	NearDuplicateKeys        *bool    `yaml:"near-duplicate-keys"`
+	NearDuplicateKeyDistance *int     `yaml:"near-duplicate-key-distance"`
+	ExcludeFiles             []string `yaml:"exclude-files"`
+
+	ReportUnusedDirectives *bool `yaml:"report-unused-directives"`
+}
+
+// merge returns the configuration of a directory whose own file is child,
+// nested below the directory configured by cfg. Settings in child override
+// those of cfg, except for ExcludeFiles, which accumulate.
+func (cfg *config) merge(child *config) *config {
+	merged := *cfg
+	if child.CapitalizedMessage != nil {
+		merged.CapitalizedMessage = child.CapitalizedMessage
+	}
+	if child.ReplaceAny != nil {
+		merged.ReplaceAny = child.ReplaceAny
+	}
+	if child.KeyNamingConvention != nil {
+		merged.KeyNamingConvention = child.KeyNamingConvention
+	}
+	if child.KeyTypeConsistency != nil {
+		merged.KeyTypeConsistency = child.KeyTypeConsistency
+	}
+	if child.KeyRegistry != nil {
+		merged.KeyRegistry = child.KeyRegistry
+	}
+	if child.NearDuplicateKeys != nil {
+		merged.NearDuplicateKeys = child.NearDuplicateKeys
+	}
+	if child.NearDuplicateKeyDistance != nil {
+		merged.NearDuplicateKeyDistance = child.NearDuplicateKeyDistance
+	}
+	if child.ReportUnusedDirectives != nil {
+		merged.ReportUnusedDirectives = child.ReportUnusedDirectives
+	}
+	merged.ExcludeFiles = append(append([]string(nil), cfg.ExcludeFiles...), child.ExcludeFiles...)
+	return &merged
+}
+
+// apply returns a copy of opts with the settings of cfg filled in wherever
+// opts leaves them unset, as described by Options.
+func (cfg *config) apply(opts *Options) *Options {
+	merged := *opts
+	if opts.unset("capitalized-message", !opts.CapitalizedMessage) && cfg.CapitalizedMessage != nil {
+		merged.CapitalizedMessage = *cfg.CapitalizedMessage
+	}
+	if opts.unset("replace-any", !opts.ReplaceAny) && cfg.ReplaceAny != nil {
+		merged.ReplaceAny = *cfg.ReplaceAny
+	}
+	if opts.unset("key-naming-convention", opts.KeyNamingConvention == "") && cfg.KeyNamingConvention != nil {
+		merged.KeyNamingConvention = *cfg.KeyNamingConvention
+	}
+	if opts.unset("key-type-consistency", !opts.KeyTypeConsistency) && cfg.KeyTypeConsistency != nil {
+		merged.KeyTypeConsistency = *cfg.KeyTypeConsistency
+	}
+	if opts.unset("key-registry", opts.KeyRegistry == "") && cfg.KeyRegistry != nil {
+		merged.KeyRegistry = *cfg.KeyRegistry
+	}
+	if opts.unset("near-duplicate-keys", !opts.NearDuplicateKeys) && cfg.NearDuplicateKeys != nil {
+		merged.NearDuplicateKeys = *cfg.NearDuplicateKeys
+	}
+	if opts.unset("near-duplicate-key-distance", opts.NearDuplicateKeyDistance == 0) && cfg.NearDuplicateKeyDistance != nil {
+		merged.NearDuplicateKeyDistance = *cfg.NearDuplicateKeyDistance
+	}
+	if opts.unset("exclude-files", len(opts.ExcludeFiles) == 0) {
+		merged.ExcludeFiles = cfg.ExcludeFiles
+	}
+	if opts.unset("report-unused-directives", !opts.ReportUnusedDirectives) && cfg.ReportUnusedDirectives != nil {
+		merged.ReportUnusedDirectives = *cfg.ReportUnusedDirectives
+	}
+	return &merged
+}
+
+// loadConfig reads and validates the configuration file at path.
+func loadConfig(path string) (*config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("zaplint: %w", err)
+	}
+
+	var cfg config
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("zaplint: %s: %w", path, err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("zaplint: %s: %w", path, err)
+	}
+	if err := validateConfig(path, &root); err != nil {
+		return nil, err
+	}
+	if cfg.KeyRegistry != nil && *cfg.KeyRegistry != "" && !filepath.IsAbs(*cfg.KeyRegistry) {
+		registry := filepath.Join(filepath.Dir(path), *cfg.KeyRegistry)
+		cfg.KeyRegistry = &registry
+	}
+	return &cfg, nil
+}
+
+// validateConfig checks the values of a configuration file that has already
+// been decoded successfully, reporting the line of the first invalid one.
+func validateConfig(path string, root *yaml.Node) error {
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
+		return nil
+	}
+
+	mapping := root.Content[0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i], mapping.Content[i+1]
+		switch key.Value {
+		case "key-naming-convention":
+			switch value.Value {
+			case "", SnakeCase, KebabCase, CamelCase, PascalCase:
+			default:
+				return fmt.Errorf("zaplint: %s:%d: key-naming-convention=%s: %w (want %s, %s, %s or %s)",
+					path, value.Line, value.Value, errInvalidValue, SnakeCase, KebabCase, CamelCase, PascalCase)
+			}
+		case "near-duplicate-key-distance":
+			if n, err := strconv.Atoi(value.Value); err == nil && n < 0 {
+				return fmt.Errorf("zaplint: %s:%d: near-duplicate-key-distance=%s: %w (want 0 or more)",
+					path, value.Line, value.Value, errInvalidValue)
+			}
+		case "exclude-files":
+			for _, item := range value.Content {
+				if _, err := regexp.Compile(item.Value); err != nil {
+					return fmt.Errorf("zaplint: %s:%d: exclude-files=%s: %w", path, item.Line, item.Value, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// findConfigs returns the paths of the configuration files in dir and its
+// parents, nearest first. At most one file is used per directory.
+//
+// The search stops at the root of the repository, the nearest directory
+// with a .git entry, so that files elsewhere, such as in the home
+// directory, do not change the results. Outside of git repositories it
+// stops at the nearest directory with a go.mod file, and without one only
+// dir is searched.
+func findConfigs(dir string) ([]string, error) {
+	var paths []string
+	own := -1  // The number of paths found in dir itself.
+	stop := -1 // The number of paths found up to the module root, if any.
+	for {
+		for _, name := range configFileNames {
+			path := filepath.Join(dir, name)
+			if ok, err := exists(path); err != nil {
+				return nil, err
+			} else if ok {
+				paths = append(paths, path)
+				break
+			}
+		}
+
+		if ok, err := exists(filepath.Join(dir, ".git")); err != nil {
+			return nil, err
+		} else if ok {
+			return paths, nil
+		}
+		if stop < 0 {
+			if ok, err := exists(filepath.Join(dir, "go.mod")); err != nil {
+				return nil, err
+			} else if ok {
+				stop = len(paths)
+			}
+		}
+		if own < 0 {
+			own = len(paths)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	if stop >= 0 {
+		return paths[:stop], nil
+	}
+	return paths[:own], nil
+}
+
+// exists reports whether a file exists at path.
+func exists(path string) (bool, error) {
+	if _, err := os.Stat(path); err == nil {
+		return true, nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return false, fmt.Errorf("zaplint: %w", err)
+	}
+	return false, nil
+}
+
+// configLoader resolves the effective options of each package. Loaded files
+// are cached, since the analyzer runs once per package and packages of the
+// same module usually share a configuration file and key registry.
+type configLoader struct {
+	mu         sync.Mutex
+	configs    map[string]*config   // keyed by file path
+	registries map[string]*registry // keyed by file path
+}
+
+func newConfigLoader() *configLoader {
+	return &configLoader{configs: make(map[string]*config), registries: make(map[string]*registry)}
+}
+
+// resolve returns the options to analyze the package of pass with: opts
+// merged with the configuration file given by opts.Config or, when that is
+// empty, the cascade of files found from the package directory upward.
+func (l *configLoader) resolve(pass *analysis.Pass, opts *Options) (*Options, error) {
+	var dir string
+	if len(pass.Files) > 0 {
+		dir = filepath.Dir(pass.Fset.Position(pass.Files[0].Package).Filename)
+	} else if opts.Config == "" {
+		return opts, nil
+	}
+	return l.resolveDir(dir, opts)
+}
+
+// resolveDir is like resolve, for the packages in dir.
+func (l *configLoader) resolveDir(dir string, opts *Options) (*Options, error) {
+	paths := []string{opts.Config}
+	if opts.Config == "" {
+		found, err := findConfigs(dir)
+		if err != nil {
+			return nil, err
+		}
+		paths = found
+	}
+
+	var cfgs []*config
+	for _, path := range paths {
+		cfg, err := l.load(path)
+		if err != nil {
+			return nil, err
+		}
+		cfgs = append(cfgs, cfg)
+		if cfg.Root {
+			break
+		}
+	}
+	if len(cfgs) == 0 {
+		return opts, nil
+	}
+
+	merged := &config{}
+	for i := len(cfgs) - 1; i >= 0; i-- {
+		merged = merged.merge(cfgs[i])
+	}
+	return merged.apply(opts), nil
+}
+
+func (l *configLoader) load(path string) (*config, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if cfg, ok := l.configs[path]; ok {
+		return cfg, nil
+	}
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	l.configs[path] = cfg
+	return cfg, nil
+}
+
+// keyRegistry returns the key registry file at path.
+func (l *configLoader) keyRegistry(path string) (*registry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if reg, ok := l.registries[path]; ok {
+		return reg, nil
+	}
+	reg, err := loadRegistry(path)
+	if err != nil {
+		return nil, err
+	}
+	l.registries[path] = reg
+	return reg, nil
+}
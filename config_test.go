@@ -0,0 +1,174 @@
+package zaplint
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{
+			name:    "valid",
+			content: "capitalized-message: true\nkey-naming-convention: snake\nexclude-files:\n  - _test\\.go$\n",
+		},
+		{
+			name: "empty",
+		},
+		{
+			name:    "unknown field",
+			content: "capitalized-message: true\nkey-naming: snake\n",
+			err:     "line 2: field key-naming not found",
+		},
+		{
+			name:    "wrong type",
+			content: "replace-any: yes please\n",
+			err:     "line 1: cannot unmarshal !!str `yes please` into bool",
+		},
+		{
+			name:    "invalid convention",
+			content: "replace-any: true\nkey-naming-convention: screaming\n",
+			err:     ".zaplint.yaml:2: key-naming-convention=screaming: invalid value",
+		},
+		{
+			name:    "negative distance",
+			content: "near-duplicate-keys: true\nnear-duplicate-key-distance: -1\n",
+			err:     ".zaplint.yaml:2: near-duplicate-key-distance=-1: invalid value",
+		},
+		{
+			name:    "invalid pattern",
+			content: "exclude-files:\n  - foo\n  - (bar\n",
+			err:     ".zaplint.yaml:3: exclude-files=(bar: error parsing regexp",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), ".zaplint.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := loadConfig(path)
+			switch {
+			case tt.err == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.err != "" && err == nil:
+				t.Fatalf("expected error containing %q", tt.err)
+			case tt.err != "" && !strings.Contains(err.Error(), tt.err):
+				t.Fatalf("error %q does not contain %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestFindConfigs(t *testing.T) {
+	t.Parallel()
+	// The file above the repository root is not used.
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	nested := filepath.Join(repo, "a", "b")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	var want []string
+	for _, path := range []string{
+		filepath.Join(nested, ".zaplint.yaml"),
+		filepath.Join(repo, "a", ".zaplint.yml"),
+		filepath.Join(repo, ".zaplint.yaml"),
+	} {
+		if err := os.WriteFile(path, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, path)
+	}
+	for _, path := range []string{
+		// Only one file is used per directory.
+		filepath.Join(repo, ".zaplint.yml"),
+		filepath.Join(root, ".zaplint.yaml"),
+		// The module root does not stop the search within a repository.
+		filepath.Join(repo, "a", "go.mod"),
+	} {
+		if err := os.WriteFile(path, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := findConfigs(nested)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("findConfigs(%s) = %v, want %v", nested, got, want)
+	}
+}
+
+func TestFindConfigsModuleRoot(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	module := filepath.Join(root, "module")
+	nested := filepath.Join(module, "a")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{
+		filepath.Join(root, ".zaplint.yaml"),
+		filepath.Join(module, ".zaplint.yaml"),
+		filepath.Join(module, "go.mod"),
+	} {
+		if err := os.WriteFile(path, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{filepath.Join(module, ".zaplint.yaml")}
+	got, err := findConfigs(nested)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("findConfigs(%s) = %v, want %v", nested, got, want)
+	}
+}
+
+func TestApplyExplicitFlags(t *testing.T) {
+	t.Parallel()
+	enabled, distance := true, 2
+	cfg := &config{
+		CapitalizedMessage:       &enabled,
+		ReplaceAny:               &enabled,
+		NearDuplicateKeyDistance: &distance,
+		ExcludeFiles:             []string{`_test\.go$`},
+	}
+
+	opts := &Options{}
+	fset := flags(opts, func() {})
+	if err := fset.Parse([]string{"-capitalized-message=false", "-near-duplicate-key-distance=0", "-exclude-files="}); err != nil {
+		t.Fatal(err)
+	}
+	got := cfg.apply(opts)
+	if got.CapitalizedMessage || got.NearDuplicateKeyDistance != 0 || len(got.ExcludeFiles) != 0 {
+		t.Errorf("explicit flags were overridden by the configuration file: %+v", got)
+	}
+	// Flags that were not given are taken from the file.
+	if !got.ReplaceAny {
+		t.Errorf("replace-any = false, want true from the configuration file")
+	}
+
+	// Options built without flags only override the file where set.
+	got = cfg.apply(&Options{NearDuplicateKeyDistance: 1})
+	if !got.CapitalizedMessage || got.NearDuplicateKeyDistance != 1 || len(got.ExcludeFiles) != 1 {
+		t.Errorf("unexpected options: %+v", got)
+	}
+}
@@ -2,7 +2,11 @@ module github.com/rleungx/zaplint
 
 go 1.23
 
-require golang.org/x/tools v0.28.0
+require (
+	github.com/golangci/plugin-module-register v0.1.1
+	golang.org/x/tools v0.28.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	golang.org/x/mod v0.22.0 // indirect
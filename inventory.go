@@ -0,0 +1,289 @@
+package zaplint
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Logger kinds of a LogCall.
+const (
+	KindLogger        = "Logger"
+	KindSugaredLogger = "SugaredLogger"
+)
+
+// LogCall is a call that writes a log entry through a zap Logger or
+// SugaredLogger, as found by the Inventory analyzer.
+type LogCall struct {
+	Pos      token.Pos
+	Function string // The enclosing function, e.g. "(*Server).Serve"; empty at package level.
+	Kind     string // KindLogger or KindSugaredLogger.
+	Method   string // The name of the method called, e.g. "Infow".
+	Level    string // The level, e.g. "info", or empty if it is not a constant.
+
+	// Message is the log message, or the format string of the methods
+	// that take one. It is only set if it is a constant, as reported by
+	// ConstantMessage.
+	Message         string
+	ConstantMessage bool
+
+	// Fields are the fields passed to the call whose keys are constant, in
+	// order. Fields built elsewhere and passed in as variables are not
+	// included.
+	Fields []Field
+}
+
+// Field is a field of a LogCall.
+type Field struct {
+	Pos         token.Pos // The position of the key, or of the constructor call if it implies the key.
+	Key         string
+	Constructor string // The field constructor, e.g. "zap.String", or empty for a loosely typed key-value pair.
+	Type        string // The type of the value, e.g. "time.Duration"; empty if the constructor takes none.
+
+	// Encoding is the field constructor that the value is encoded with:
+	// Constructor itself, or for zap.Any and loosely typed pairs the
+	// constructor that zap picks for the type of the value, as suggested
+	// by the replace-any rule. It is empty when that is not known.
+	Encoding string
+}
+
+// Inventory is an analyzer that reports no diagnostics, but returns the
+// log calls of each package as a []*LogCall, in source order.
+var Inventory = &analysis.Analyzer{
+	Name:       "zapinventory",
+	Doc:        "collect the zap logging calls of each package",
+	URL:        "https://github.com/rleungx/zaplint",
+	Requires:   []*analysis.Analyzer{inspect.Analyzer},
+	ResultType: reflect.TypeOf([]*LogCall(nil)),
+	Run: func(pass *analysis.Pass) (any, error) {
+		visitor := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+		filter := []ast.Node{(*ast.CallExpr)(nil)}
+
+		calls := []*LogCall{}
+		visitor.WithStack(filter, func(node ast.Node, push bool, stack []ast.Node) bool {
+			if !push {
+				return true
+			}
+			if call := inventoryCall(pass, node.(*ast.CallExpr)); call != nil {
+				call.Function = enclosingFunction(stack)
+				calls = append(calls, call)
+			}
+			return true
+		})
+		return calls, nil
+	},
+}
+
+// levelNames maps the values of zapcore.Level to their names.
+var levelNames = map[int64]string{
+	-1: "debug",
+	0:  "info",
+	1:  "warn",
+	2:  "error",
+	3:  "dpanic",
+	4:  "panic",
+	5:  "fatal",
+}
+
+// inventoryCall returns the LogCall of call, or nil if it does not write a
+// log entry.
+func inventoryCall(pass *analysis.Pass, call *ast.CallExpr) *LogCall {
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil {
+		return nil
+	}
+	name := fullName(fn)
+	index, ok := logMethods[name]
+	if !ok {
+		return nil
+	}
+
+	c := &LogCall{Pos: call.Pos(), Kind: KindLogger, Method: fn.Name()}
+	if strings.HasPrefix(name, "(*go.uber.org/zap.SugaredLogger).") {
+		c.Kind = KindSugaredLogger
+	}
+
+	if index == 0 {
+		lvl := fn.Name()
+		if c.Kind == KindSugaredLogger {
+			for _, suffix := range []string{"ln", "f", "w"} {
+				if trimmed, ok := strings.CutSuffix(lvl, suffix); ok {
+					if _, ok := level[trimmed]; ok {
+						lvl = trimmed
+						break
+					}
+				}
+			}
+		}
+		c.Level = strings.ToLower(lvl)
+	} else if tv := pass.TypesInfo.Types[call.Args[0]]; tv.Value != nil {
+		if v, ok := constant.Int64Val(tv.Value); ok {
+			c.Level = levelNames[v]
+		}
+	}
+
+	if len(call.Args) > index {
+		if tv := pass.TypesInfo.Types[call.Args[index]]; tv.Value != nil && tv.Value.Kind() == constant.String {
+			c.Message = constant.StringVal(tv.Value)
+			c.ConstantMessage = true
+		}
+	}
+
+	switch {
+	case c.Kind == KindLogger && c.Method != "Check":
+		if !call.Ellipsis.IsValid() {
+			for _, arg := range call.Args[index+1:] {
+				if f, ok := zapField(pass, arg); ok {
+					c.Fields = append(c.Fields, f)
+				}
+			}
+		}
+	case strings.HasSuffix(c.Method, "w"):
+		c.Fields = keysAndValues(pass, call, sugaredMethods[name])
+	}
+	return c
+}
+
+// zapField returns the field built by expr, if it is a call to a field
+// constructor of zap with a constant key.
+func zapField(pass *analysis.Pass, expr ast.Expr) (Field, bool) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return Field{}, false
+	}
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil || fn.Pkg() == nil || trimVendor(fn.Pkg().Path()) != "go.uber.org/zap" {
+		return Field{}, false
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.Results().Len() != 1 || !isZapField(sig.Results().At(0).Type()) {
+		return Field{}, false
+	}
+
+	f := Field{Pos: call.Pos(), Constructor: "zap." + fn.Name(), Encoding: "zap." + fn.Name()}
+	switch {
+	case fn.Name() == "Error":
+		// zap.Error(err) is zap.NamedError("error", err).
+		f.Key = "error"
+		if len(call.Args) == 1 {
+			f.Type = typeString(pass, call.Args[0])
+		}
+	case sig.Params().Len() > 0 && isStringType(sig.Params().At(0).Type()) && len(call.Args) > 0:
+		tv := pass.TypesInfo.Types[call.Args[0]]
+		if tv.Value == nil || tv.Value.Kind() != constant.String {
+			return Field{}, false
+		}
+		f.Pos = call.Args[0].Pos()
+		f.Key = constant.StringVal(tv.Value)
+		if len(call.Args) > 1 && !call.Ellipsis.IsValid() {
+			f.Type = typeString(pass, call.Args[1])
+			if fn.Name() == "Any" {
+				f.Encoding = encoding(pass, call.Args[1])
+			}
+		}
+	default:
+		// Fields without a key, such as zap.Inline or zap.Skip.
+		return Field{}, false
+	}
+	return f, true
+}
+
+// keysAndValues returns the fields passed to a SugaredLogger method as
+// key-value pairs or strongly typed fields, starting at the argument with
+// the given index.
+func keysAndValues(pass *analysis.Pass, call *ast.CallExpr, start int) []Field {
+	if call.Ellipsis.IsValid() || len(call.Args) <= start {
+		return nil
+	}
+
+	var fields []Field
+	args := call.Args[start:]
+	for i := 0; i < len(args); i++ {
+		if isZapField(pass.TypesInfo.TypeOf(args[i])) {
+			if f, ok := zapField(pass, args[i]); ok {
+				fields = append(fields, f)
+			}
+			continue
+		}
+		if i == len(args)-1 {
+			break
+		}
+		key, value := args[i], args[i+1]
+		i++
+		if tv := pass.TypesInfo.Types[key]; tv.Value != nil && tv.Value.Kind() == constant.String {
+			fields = append(fields, Field{
+				Pos:      key.Pos(),
+				Key:      constant.StringVal(tv.Value),
+				Type:     typeString(pass, value),
+				Encoding: encoding(pass, value),
+			})
+		}
+	}
+	return fields
+}
+
+// encoding returns the field constructor that zap.Any uses for the value of
+// expr, or "" if it is not known.
+func encoding(pass *analysis.Pass, expr ast.Expr) string {
+	t := pass.TypesInfo.TypeOf(expr)
+	if t == nil {
+		return ""
+	}
+	if name := getType(types.Default(t)); name != "" {
+		return "zap." + name
+	}
+	return ""
+}
+
+// typeString returns the type of expr, qualified by package name. Untyped
+// constants are reported with their default type.
+func typeString(pass *analysis.Pass, expr ast.Expr) string {
+	t := pass.TypesInfo.TypeOf(expr)
+	if t == nil {
+		return ""
+	}
+	return types.TypeString(types.Default(t), func(pkg *types.Package) string {
+		return pkg.Name()
+	})
+}
+
+// enclosingFunction returns the name of the function declaration in stack,
+// such as "(*Server).Serve" for a method, or "" if there is none.
+func enclosingFunction(stack []ast.Node) string {
+	for i := len(stack) - 1; i >= 0; i-- {
+		decl, ok := stack[i].(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		if decl.Recv == nil || len(decl.Recv.List) == 0 {
+			return decl.Name.Name
+		}
+		recv := decl.Recv.List[0].Type
+		star := ""
+		if s, ok := recv.(*ast.StarExpr); ok {
+			star, recv = "*", s.X
+		}
+		switch r := recv.(type) {
+		case *ast.IndexExpr:
+			recv = r.X
+		case *ast.IndexListExpr:
+			recv = r.X
+		}
+		if id, ok := recv.(*ast.Ident); ok {
+			if star != "" {
+				return "(*" + id.Name + ")." + decl.Name.Name
+			}
+			return id.Name + "." + decl.Name.Name
+		}
+		return decl.Name.Name
+	}
+	return ""
+}
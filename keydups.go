@@ -0,0 +1,257 @@
+package zaplint
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// keyCountsFact records how often a package logs each key, and where it
+// does so first. Packages import the facts of their dependencies to find
+// near-duplicate keys, such as "user_id" and "userid", and the most common
+// spelling among them.
+type keyCountsFact struct {
+	Keys     map[string]keyCount
+	Imported []string // The keys logged by the dependencies of the package, sorted.
+}
+
+func (*keyCountsFact) AFact() {}
+
+func (f *keyCountsFact) String() string {
+	var keys []string
+	for key, c := range f.Keys {
+		keys = append(keys, fmt.Sprintf("%s:%d", key, c.Uses))
+	}
+	slices.Sort(keys)
+	return "keyCounts(" + strings.Join(keys, ", ") + ")"
+}
+
+// has reports whether the package of f or its dependencies log both keys,
+// in which case their near-duplicates have been reported there.
+func (f *keyCountsFact) has(key1, key2 string) bool {
+	return f.logs(key1) && f.logs(key2)
+}
+
+// logs reports whether the package of f or its dependencies log key.
+func (f *keyCountsFact) logs(key string) bool {
+	if _, ok := f.Keys[key]; ok {
+		return true
+	}
+	_, ok := slices.BinarySearch(f.Imported, key)
+	return ok
+}
+
+// keyCount is the number of uses of a key and the position of the first one.
+type keyCount struct {
+	Uses     int
+	Filename string
+	Line     int
+	Column   int
+}
+
+// keyCounts tracks the keys logged by the current package and its
+// dependencies.
+type keyCounts struct {
+	distance int
+	total    map[string]keyCount               // The uses in the package and its dependencies.
+	own      map[string]keyCount               // The uses in the package.
+	fields   []Field                           // The fields of the package, in source order.
+	facts    map[*types.Package]*keyCountsFact // The facts of the dependencies.
+	clusters map[string][]string               // The near-duplicates of keys, by clusterOf.
+}
+
+// newKeyCounts returns the key counts of the dependencies of the package of
+// pass. Keys are near-duplicates when they are spelled with the same words,
+// or when their words differ by at most distance edits.
+func newKeyCounts(pass *analysis.Pass, distance int) *keyCounts {
+	kc := &keyCounts{
+		distance: distance,
+		total:    make(map[string]keyCount),
+		own:      make(map[string]keyCount),
+		facts:    make(map[*types.Package]*keyCountsFact),
+		clusters: make(map[string][]string),
+	}
+	facts := pass.AllPackageFacts()
+	slices.SortFunc(facts, func(a, b analysis.PackageFact) int {
+		return strings.Compare(a.Package.Path(), b.Package.Path())
+	})
+	for _, f := range facts {
+		fact, ok := f.Fact.(*keyCountsFact)
+		if !ok || f.Package == pass.Pkg {
+			continue
+		}
+		kc.facts[f.Package] = fact
+		for key, c := range fact.Keys {
+			kc.total[key] = addKeyCount(kc.total[key], c)
+		}
+	}
+	return kc
+}
+
+// addKeyCount adds the uses of b to a, keeping the first position.
+func addKeyCount(a, b keyCount) keyCount {
+	if a.Uses == 0 {
+		return b
+	}
+	a.Uses += b.Uses
+	return a
+}
+
+// visit records the keys logged by call.
+func (kc *keyCounts) visit(pass *analysis.Pass, call *ast.CallExpr) {
+	for _, f := range loggedFields(pass, call) {
+		posn := pass.Fset.Position(f.Pos)
+		c := keyCount{Uses: 1, Filename: posn.Filename, Line: posn.Line, Column: posn.Column}
+		kc.own[f.Key] = addKeyCount(kc.own[f.Key], c)
+		kc.total[f.Key] = addKeyCount(kc.total[f.Key], c)
+		kc.fields = append(kc.fields, f)
+	}
+}
+
+// report reports the uses of keys in the package that have a more common
+// near-duplicate in the package or its dependencies. Keys logged by a
+// dependency are reported at its import, among files, when the more common
+// spelling is only logged by packages that it does not depend on.
+func (kc *keyCounts) report(pass *analysis.Pass, files []*ast.File) {
+	for _, f := range kc.fields {
+		kc.reportKey(pass, f.Key, f.Pos, f.Pos, "")
+	}
+
+	imports := slices.Clone(pass.Pkg.Imports())
+	slices.SortFunc(imports, func(a, b *types.Package) int {
+		return strings.Compare(a.Path(), b.Path())
+	})
+	reported := make(map[string]bool)
+	for _, imp := range imports {
+		fact, ok := kc.facts[imp]
+		if !ok {
+			continue
+		}
+		spec := importSpec(pass, files, imp)
+		if spec == nil {
+			continue
+		}
+		keys := slices.Concat(slices.Collect(maps.Keys(fact.Keys)), fact.Imported)
+		slices.Sort(keys)
+		for _, key := range slices.Compact(keys) {
+			canonical := kc.clusterOf(key)[0]
+			if reported[key] || canonical == key || fact.logs(canonical) || kc.reportedIn(key, canonical) {
+				continue
+			}
+			reported[key] = true
+			kc.reportKey(pass, key, spec.Pos(), spec.End(), " in "+imp.Path())
+		}
+	}
+}
+
+// reportKey reports a use of key from pos to end if it has a more common
+// near-duplicate, with where added to the key in the message.
+func (kc *keyCounts) reportKey(pass *analysis.Pass, key string, pos, end token.Pos, where string) {
+	cl := kc.clusterOf(key)
+	canonical := cl[0]
+	if canonical == key {
+		return
+	}
+
+	var others []string
+	for _, k := range cl {
+		if k != key {
+			others = append(others, fmt.Sprintf("'%s' (%s)", k, pluralUses(kc.total[k].Uses)))
+		}
+	}
+	diag := analysis.Diagnostic{
+		Pos:      pos,
+		Category: ruleNearDuplicateKeys,
+		Message: fmt.Sprintf("key '%s' (%s)%s is a near-duplicate of %s; use '%s', the most common spelling",
+			key, pluralUses(kc.total[key].Uses), where, strings.Join(others, ", "), canonical),
+	}
+	if end != pos {
+		diag.End = end
+	}
+	c := kc.total[canonical]
+	if p := findPos(pass.Fset, c.Filename, c.Line, c.Column); p.IsValid() {
+		diag.Related = []analysis.RelatedInformation{{
+			Pos:     p,
+			Message: fmt.Sprintf("key '%s' is logged here", canonical),
+		}}
+	}
+	pass.Report(diag)
+}
+
+// clusterOf returns the near-duplicates of key, most common first and then
+// in case-insensitive alphabetical order, which puts "user_id" before
+// "userId". The first one is the canonical spelling.
+func (kc *keyCounts) clusterOf(key string) []string {
+	if cl, ok := kc.clusters[key]; ok {
+		return cl
+	}
+	var cl []string
+	for k := range kc.total {
+		if k == key || kc.similar(key, k) {
+			cl = append(cl, k)
+		}
+	}
+	slices.SortFunc(cl, func(a, b string) int {
+		return cmp.Or(
+			cmp.Compare(kc.total[b].Uses, kc.total[a].Uses),
+			strings.Compare(strings.ToLower(a), strings.ToLower(b)),
+			strings.Compare(a, b),
+		)
+	})
+	kc.clusters[key] = cl
+	return cl
+}
+
+// reportedIn reports whether a dependency logs both keys, and so has
+// reported them already.
+func (kc *keyCounts) reportedIn(key1, key2 string) bool {
+	for _, fact := range kc.facts {
+		if fact.has(key1, key2) {
+			return true
+		}
+	}
+	return false
+}
+
+// similar reports whether the keys a and b are near-duplicates. The edit
+// distance is only applied to keys longer than twice the distance, since
+// short keys such as "id" and "ip" are often that close by chance.
+func (kc *keyCounts) similar(a, b string) bool {
+	a, b = normalizeKey(a), normalizeKey(b)
+	if a == b {
+		return true
+	}
+	la, lb := len([]rune(a)), len([]rune(b))
+	if kc.distance <= 0 || min(la, lb) <= 2*kc.distance || max(la-lb, lb-la) > kc.distance {
+		return false
+	}
+	return editDistance(a, b) <= kc.distance
+}
+
+// export exports the key counts of the package, and the keys of its
+// dependencies, as a fact.
+func (kc *keyCounts) export(pass *analysis.Pass) {
+	var imported []string
+	for _, fact := range kc.facts {
+		imported = append(imported, slices.Collect(maps.Keys(fact.Keys))...)
+	}
+	slices.Sort(imported)
+	imported = slices.Compact(imported)
+	if len(kc.own) > 0 || len(imported) > 0 {
+		pass.ExportPackageFact(&keyCountsFact{Keys: kc.own, Imported: imported})
+	}
+}
+
+func pluralUses(n int) string {
+	if n == 1 {
+		return "1 use"
+	}
+	return fmt.Sprintf("%d uses", n)
+}
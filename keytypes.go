@@ -0,0 +1,327 @@
+package zaplint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// keyTypesFact records, for each key logged by a package or by the packages
+// it imports, the first use of each kind of value it is logged with.
+// Packages import the facts of their dependencies to find keys logged with
+// different kinds of values, which break the mappings of log indexes.
+type keyTypesFact struct {
+	Keys map[string][]keyUse
+}
+
+func (*keyTypesFact) AFact() {}
+
+func (f *keyTypesFact) String() string {
+	var keys []string
+	for key, uses := range f.Keys {
+		for _, use := range uses {
+			keys = append(keys, key+":"+use.Encoding)
+		}
+	}
+	slices.Sort(keys)
+	return "keyTypes(" + strings.Join(keys, ", ") + ")"
+}
+
+// has reports whether the key is logged with both kinds of values in the
+// package of f or its dependencies, in which case the conflict has been
+// reported there.
+func (f *keyTypesFact) has(key, kind1, kind2 string) bool {
+	hasKind := func(kind string) bool {
+		return slices.ContainsFunc(f.Keys[key], func(u keyUse) bool { return u.Kind == kind })
+	}
+	return hasKind(kind1) && hasKind(kind2)
+}
+
+// keyUse is a use of a key with a given kind of value. Its position is kept
+// as a file name and line, since facts may outlive the file set of the
+// package that exported them.
+type keyUse struct {
+	Kind     string // The kind of the value, as returned by encodedKind.
+	Encoding string // The field constructor that the value is encoded with, e.g. "zap.String".
+	Package  string
+	Filename string
+	Line     int
+	Column   int
+}
+
+// keyTypes tracks the kinds of values that keys are logged with, in the
+// current package and in its dependencies.
+type keyTypes struct {
+	deps map[string][]keyUse
+	own  map[string][]keyUse
+}
+
+// newKeyTypes returns the key types of the dependencies of the package of
+// pass, ordered by package path for reproducible reports.
+//
+// Keys logged with different kinds of values by packages that do not import
+// one another are reported at the import, among files, of the direct
+// dependency that brings in the second kind, unless a dependency imports
+// both packages and has reported the conflict already.
+func newKeyTypes(pass *analysis.Pass, files []*ast.File) *keyTypes {
+	kt := &keyTypes{deps: make(map[string][]keyUse), own: make(map[string][]keyUse)}
+	facts := make(map[*types.Package]*keyTypesFact)
+	var deps []*types.Package
+	for _, f := range pass.AllPackageFacts() {
+		if fact, ok := f.Fact.(*keyTypesFact); ok && f.Package != pass.Pkg {
+			facts[f.Package] = fact
+			deps = append(deps, f.Package)
+		}
+	}
+	byPath := func(a, b *types.Package) int {
+		return strings.Compare(a.Path(), b.Path())
+	}
+	slices.SortFunc(deps, byPath)
+	reported := func(key, kind1, kind2 string) bool {
+		for _, fact := range facts {
+			if fact.has(key, kind1, kind2) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// The facts of the direct dependencies cover their own dependencies,
+	// except those imported through packages where the rule is disabled,
+	// which are merged afterwards.
+	imports := slices.Clone(pass.Pkg.Imports())
+	slices.SortFunc(imports, byPath)
+	for _, imp := range imports {
+		fact, ok := facts[imp]
+		if !ok {
+			continue
+		}
+		for _, key := range slices.Sorted(maps.Keys(fact.Keys)) {
+			for _, use := range fact.Keys[key] {
+				if other, ok := conflictingUse(kt.deps[key], use.Kind); ok && !reported(key, other.Kind, use.Kind) {
+					reportImportConflict(pass, files, imp, key, use, other)
+				}
+				kt.deps[key] = addKeyUse(kt.deps[key], use)
+			}
+		}
+	}
+	for _, dep := range deps {
+		for key, uses := range facts[dep].Keys {
+			for _, use := range uses {
+				kt.deps[key] = addKeyUse(kt.deps[key], use)
+			}
+		}
+	}
+	return kt
+}
+
+// reportImportConflict reports the import of imp, which logs key as in use
+// while another dependency logs it as in other.
+func reportImportConflict(pass *analysis.Pass, files []*ast.File, imp *types.Package, key string, use, other keyUse) {
+	spec := importSpec(pass, files, imp)
+	if spec == nil {
+		return
+	}
+	diag := analysis.Diagnostic{
+		Pos:      spec.Pos(),
+		End:      spec.End(),
+		Category: ruleKeyTypeConsistency,
+		Message: fmt.Sprintf("key '%s' is logged as %s in %s, but as %s in %s",
+			key, use.Encoding, use.Package, other.Encoding, other.Package),
+	}
+	if pos := findPos(pass.Fset, other.Filename, other.Line, other.Column); pos.IsValid() {
+		diag.Related = []analysis.RelatedInformation{{
+			Pos:     pos,
+			Message: fmt.Sprintf("key '%s' is logged as %s here", key, other.Encoding),
+		}}
+	}
+	pass.Report(diag)
+}
+
+// importSpec returns the first import of imp among files, or nil if none
+// of them imports it.
+func importSpec(pass *analysis.Pass, files []*ast.File, imp *types.Package) *ast.ImportSpec {
+	for _, file := range files {
+		for _, spec := range file.Imports {
+			if name := pass.TypesInfo.PkgNameOf(spec); name != nil && name.Imported() == imp {
+				return spec
+			}
+		}
+	}
+	return nil
+}
+
+func addKeyUse(uses []keyUse, use keyUse) []keyUse {
+	if slices.ContainsFunc(uses, func(u keyUse) bool { return u.Kind == use.Kind }) {
+		return uses
+	}
+	return append(uses, use)
+}
+
+// visit records the keys logged by call and reports those already logged
+// with another kind of value, in a dependency or earlier in the package.
+func (kt *keyTypes) visit(pass *analysis.Pass, call *ast.CallExpr) {
+	for _, f := range loggedFields(pass, call) {
+		kind := encodedKind(f.Encoding)
+		if kind == "" {
+			continue
+		}
+		posn := pass.Fset.Position(f.Pos)
+		use := keyUse{
+			Kind:     kind,
+			Encoding: f.Encoding,
+			Package:  pass.Pkg.Path(),
+			Filename: posn.Filename,
+			Line:     posn.Line,
+			Column:   posn.Column,
+		}
+
+		where := ""
+		other, ok := conflictingUse(kt.deps[f.Key], kind)
+		if ok {
+			where = "in " + other.Package
+		} else if other, ok = conflictingUse(kt.own[f.Key], kind); ok {
+			where = "elsewhere in this package"
+		}
+		if ok {
+			diag := analysis.Diagnostic{
+				Pos:      f.Pos,
+				Category: ruleKeyTypeConsistency,
+				Message:  fmt.Sprintf("key '%s' is logged as %s, but as %s %s", f.Key, f.Encoding, other.Encoding, where),
+			}
+			if pos := findPos(pass.Fset, other.Filename, other.Line, other.Column); pos.IsValid() {
+				diag.Related = []analysis.RelatedInformation{{
+					Pos:     pos,
+					Message: fmt.Sprintf("key '%s' is logged as %s here", f.Key, other.Encoding),
+				}}
+			}
+			pass.Report(diag)
+		}
+		kt.own[f.Key] = addKeyUse(kt.own[f.Key], use)
+	}
+}
+
+// loggedFields returns the field built by call, if it is a call to a field
+// constructor of zap, or the loosely typed key-value pairs passed to call,
+// if it is a call to a SugaredLogger method. Each field of a package is
+// returned once when visiting all of its calls, since strongly typed fields
+// passed to SugaredLogger methods are visited on their own.
+func loggedFields(pass *analysis.Pass, call *ast.CallExpr) []Field {
+	if f, ok := zapField(pass, call); ok {
+		return []Field{f}
+	}
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil {
+		return nil
+	}
+	start, ok := sugaredMethods[fullName(fn)]
+	if !ok {
+		return nil
+	}
+	var fields []Field
+	for _, f := range keysAndValues(pass, call, start) {
+		if f.Constructor == "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// export exports the keys of the package and its dependencies as a fact.
+func (kt *keyTypes) export(pass *analysis.Pass) {
+	keys := make(map[string][]keyUse)
+	for _, m := range []map[string][]keyUse{kt.own, kt.deps} {
+		for key, uses := range m {
+			for _, use := range uses {
+				keys[key] = addKeyUse(keys[key], use)
+			}
+		}
+	}
+	if len(keys) > 0 {
+		pass.ExportPackageFact(&keyTypesFact{Keys: keys})
+	}
+}
+
+// conflictingUse returns the first of uses with a kind other than kind.
+func conflictingUse(uses []keyUse, kind string) (keyUse, bool) {
+	for _, use := range uses {
+		if use.Kind != kind {
+			return use, true
+		}
+	}
+	return keyUse{}, false
+}
+
+// findPos returns the position of the given line and column of filename in
+// fset, or token.NoPos if the file is not part of fset, as happens when
+// packages are analyzed separately.
+func findPos(fset *token.FileSet, filename string, line, column int) token.Pos {
+	pos := token.NoPos
+	fset.Iterate(func(f *token.File) bool {
+		if f.Name() != filename {
+			return true
+		}
+		if line <= f.LineCount() {
+			start := f.LineStart(line)
+			if offset := f.Offset(start) + column - 1; offset <= f.Size() {
+				pos = f.Pos(offset)
+			}
+		}
+		return false
+	})
+	return pos
+}
+
+// isFieldConstructor reports whether name, such as "zap.Object", is a field
+// constructor of zap that takes a key: one whose encoding encodedKind
+// knows, or zap.Any and zap.Reflect, which may encode any value.
+func isFieldConstructor(name string) bool {
+	return encodedKind(name) != "" || name == "zap.Any" || name == "zap.Reflect"
+}
+
+// encodedKind returns the kind of values written by the field constructor
+// encoding in zap's JSON encoder, such as "integer" for both zap.Int and
+// zap.Int64, or "" if it may write any value. Values of different kinds
+// cannot be indexed under the same key.
+func encodedKind(encoding string) string {
+	name := strings.TrimPrefix(encoding, "zap.")
+	switch name {
+	case "Bool":
+		return "boolean"
+	case "Int", "Int64", "Int32", "Int16", "Int8", "Uint", "Uint64", "Uint32", "Uint16", "Uint8", "Uintptr":
+		return "integer"
+	case "Float64", "Float32":
+		return "number"
+	case "String", "ByteString", "Stringer", "Complex128", "Complex64", "NamedError", "Error", "Binary", "Stack", "StackSkip":
+		return "string"
+	case "Duration":
+		return "duration"
+	case "Time":
+		return "time"
+	case "Object", "Dict", "Namespace", "Inline":
+		return "object"
+	case "Array":
+		return "array"
+	case "Objects", "ObjectValues", "Errors":
+		return "array of object"
+	}
+
+	if elem, ok := strings.CutSuffix(name, "p"); ok {
+		if kind := encodedKind(elem); kind != "" && kind != "object" {
+			return kind
+		}
+	}
+	if elem, ok := strings.CutSuffix(name, "s"); ok {
+		if kind := encodedKind(elem); kind != "" {
+			return "array of " + kind
+		}
+	}
+	return ""
+}
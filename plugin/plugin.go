@@ -0,0 +1,89 @@
+// Package plugin registers zaplint as a golangci-lint module plugin.
+//
+// To use it, reference this package in .custom-gcl.yml:
+//
+//	plugins:
+//	  - module: github.com/rleungx/zaplint
+//	    import: github.com/rleungx/zaplint/plugin
+//	    version: latest
+//
+// and enable the linter in .golangci.yml:
+//
+//	linters-settings:
+//	  custom:
+//	    zaplint:
+//	      type: module
+//	      settings:
+//	        capitalized-message: true
+//	        key-naming-convention: snake
+package plugin
+
+import (
+	"github.com/golangci/plugin-module-register/register"
+	"github.com/rleungx/zaplint"
+	"golang.org/x/tools/go/analysis"
+)
+
+func init() {
+	register.Plugin("zaplint", New)
+}
+
+// Settings are the settings of the zaplint linter in the golangci-lint
+// configuration. Each field mirrors the command line flag of the same name.
+type Settings struct {
+	CapitalizedMessage       bool     `json:"capitalized-message"`
+	ReplaceAny               bool     `json:"replace-any"`
+	KeyNamingConvention      string   `json:"key-naming-convention"`
+	KeyTypeConsistency       bool     `json:"key-type-consistency"`
+	KeyRegistry              string   `json:"key-registry"`
+	NearDuplicateKeys        bool     `json:"near-duplicate-keys"`
+	NearDuplicateKeyDistance int      `json:"near-duplicate-key-distance"`
+	ExcludeFiles             []string `json:"exclude-files"`
+	Config                   string   `json:"config"`
+
+	ReportUnusedDirectives bool `json:"report-unused-directives"`
+}
+
+type plugin struct {
+	settings Settings
+	names    []string // The names of the settings that were given.
+}
+
+// New creates the zaplint plugin from the raw golangci-lint settings.
+func New(settings any) (register.LinterPlugin, error) {
+	s, err := register.DecodeSettings[Settings](settings)
+	if err != nil {
+		return nil, err
+	}
+	p := &plugin{settings: s}
+	if raw, ok := settings.(map[string]any); ok {
+		for name := range raw {
+			p.names = append(p.names, name)
+		}
+	}
+	return p, nil
+}
+
+func (p *plugin) BuildAnalyzers() ([]*analysis.Analyzer, error) {
+	opts := &zaplint.Options{
+		CapitalizedMessage:       p.settings.CapitalizedMessage,
+		ReplaceAny:               p.settings.ReplaceAny,
+		KeyNamingConvention:      p.settings.KeyNamingConvention,
+		KeyTypeConsistency:       p.settings.KeyTypeConsistency,
+		KeyRegistry:              p.settings.KeyRegistry,
+		NearDuplicateKeys:        p.settings.NearDuplicateKeys,
+		NearDuplicateKeyDistance: p.settings.NearDuplicateKeyDistance,
+		ExcludeFiles:             p.settings.ExcludeFiles,
+		Config:                   p.settings.Config,
+
+		ReportUnusedDirectives: p.settings.ReportUnusedDirectives,
+	}
+	// Like flags, the settings that were given take precedence over the
+	// configuration file, even when they turn a rule off.
+	opts.MarkExplicit(p.names...)
+	return []*analysis.Analyzer{zaplint.New(opts)}, nil
+}
+
+func (p *plugin) GetLoadMode() string {
+	return register.LoadModeTypesInfo
+}
@@ -0,0 +1,83 @@
+package plugin_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golangci/plugin-module-register/register"
+	_ "github.com/rleungx/zaplint/plugin"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestPlugin(t *testing.T) {
+	t.Parallel()
+	newPlugin, err := register.GetPlugin("zaplint")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := newPlugin(map[string]any{
+		"key-naming-convention": "snake",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := p.GetLoadMode(); mode != register.LoadModeTypesInfo {
+		t.Fatalf("GetLoadMode() = %s, want %s", mode, register.LoadModeTypesInfo)
+	}
+
+	analyzers, err := p.BuildAnalyzers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata, err := filepath.Abs(filepath.Join("..", "testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, testdata, analyzers[0], "key_naming_convention/snake")
+}
+
+func TestPluginSettingsOverrideConfig(t *testing.T) {
+	t.Parallel()
+	newPlugin, err := register.GetPlugin("zaplint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := filepath.Join(t.TempDir(), ".zaplint.yaml")
+	if err := os.WriteFile(config, []byte("capitalized-message: true\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The messages of the package are not capitalized, but the rule enabled
+	// in the configuration file is turned off in the settings.
+	p, err := newPlugin(map[string]any{
+		"config":                config,
+		"capitalized-message":   false,
+		"key-naming-convention": "snake",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	analyzers, err := p.BuildAnalyzers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata, err := filepath.Abs(filepath.Join("..", "testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, testdata, analyzers[0], "key_naming_convention/snake")
+}
+
+func TestPluginUnknownSetting(t *testing.T) {
+	t.Parallel()
+	newPlugin, err := register.GetPlugin("zaplint")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := newPlugin(map[string]any{"key-naming": "snake"}); err == nil {
+		t.Fatal("expected an error for an unknown setting")
+	}
+}
@@ -0,0 +1,241 @@
+package zaplint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+	"gopkg.in/yaml.v3"
+)
+
+// registry is the content of a key registry file, which lists the keys that
+// may be logged:
+//
+//	keys:
+//	  user_id:
+//	    constructor: zap.String
+//	    description: The ID of the authenticated user.
+//	  latency:
+//	    type: time.Duration
+//	  trace_id:
+type registry struct {
+	Keys  map[string]*registryKey `yaml:"keys"`
+	names []string                // The registered keys, sorted.
+}
+
+// registryKey describes a registered key. Keys without a constructor or a
+// type may be logged with any value.
+type registryKey struct {
+	Constructor string `yaml:"constructor"` // The field constructor to log the key with, e.g. "zap.String".
+	Type        string `yaml:"type"`        // The type of the value, e.g. "time.Duration", as in Field.Type.
+	Description string `yaml:"description"`
+}
+
+// loadRegistry reads and validates the key registry file at path.
+func loadRegistry(path string) (*registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("zaplint: %w", err)
+	}
+
+	var reg registry
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&reg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("zaplint: %s: %w", path, err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("zaplint: %s: %w", path, err)
+	}
+	if err := validateRegistry(path, &root); err != nil {
+		return nil, err
+	}
+
+	for name := range reg.Keys {
+		reg.names = append(reg.names, name)
+	}
+	slices.Sort(reg.names)
+	return &reg, nil
+}
+
+// validateRegistry checks the constructors of a registry file that has
+// already been decoded successfully, reporting the line of the first
+// invalid one.
+func validateRegistry(path string, root *yaml.Node) error {
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
+		return nil
+	}
+
+	mapping := root.Content[0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != "keys" {
+			continue
+		}
+		keys := mapping.Content[i+1]
+		for j := 0; j+1 < len(keys.Content); j += 2 {
+			entry := keys.Content[j+1]
+			for k := 0; k+1 < len(entry.Content); k += 2 {
+				field, value := entry.Content[k], entry.Content[k+1]
+				if field.Value != "constructor" {
+					continue
+				}
+				if !strings.HasPrefix(value.Value, "zap.") || !isFieldConstructor(value.Value) {
+					return fmt.Errorf("zaplint: %s:%d: constructor=%s: %w (want a field constructor of zap, e.g. zap.String)",
+						path, value.Line, value.Value, errInvalidValue)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// closest returns the registered key that key is most likely a misspelling
+// of, or "" if none is close enough. Keys are compared by their letters and
+// digits, case-insensitively, so that "userID" is close to "user_id".
+func (reg *registry) closest(key string) string {
+	normalized := normalizeKey(key)
+	best, bestDistance := "", len([]rune(normalized))/4+1
+	for _, name := range reg.names {
+		if d := editDistance(normalized, normalizeKey(name)); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// normalizeKey returns the letters and digits of key in lower case.
+func normalizeKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, key)
+}
+
+// editDistance returns the Levenshtein distance between a and b, counting
+// runes.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	prev := make([]int, len(t)+1)
+	cur := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		cur[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(t)]
+}
+
+// checkKeyRegistry reports the keys passed to a field constructor or a
+// SugaredLogger method that are not registered in reg, or that are logged
+// with another constructor or type than registered.
+func checkKeyRegistry(pass *analysis.Pass, reg *registry, call *ast.CallExpr) {
+	if f, ok := zapField(pass, call); ok {
+		// zap.Error logs its argument under the implicit key "error".
+		at, value := ast.Expr(call), ast.Expr(nil)
+		if f.Constructor == "zap.Error" {
+			value = call.Args[0]
+		} else {
+			at = call.Args[0]
+			if len(call.Args) > 1 && !call.Ellipsis.IsValid() {
+				value = call.Args[1]
+			}
+		}
+		checkRegisteredKey(pass, reg, f.Key, at, f.Constructor, value)
+		return
+	}
+
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil {
+		return
+	}
+	start, ok := sugaredMethods[fullName(fn)]
+	if !ok || call.Ellipsis.IsValid() || len(call.Args) <= start {
+		return
+	}
+	args := call.Args[start:]
+	for i := 0; i+1 < len(args); i++ {
+		// Strongly typed fields are checked on their own.
+		if isZapField(pass.TypesInfo.TypeOf(args[i])) {
+			continue
+		}
+		if key, _, _, ok := constantString(pass, args[i]); ok {
+			checkRegisteredKey(pass, reg, key, args[i], "", args[i+1])
+		}
+		i++
+	}
+}
+
+// checkRegisteredKey checks the key logged at expr with the value of the
+// given expression through constructor, or as a loosely typed pair if
+// constructor is empty. Keys that are not constant strings are left to the
+// key-naming-convention rule.
+func checkRegisteredKey(pass *analysis.Pass, reg *registry, keyValue string, expr ast.Expr, constructor string, value ast.Expr) {
+	_, key, obj, _ := constantString(pass, expr)
+
+	entry, ok := reg.Keys[keyValue]
+	if !ok {
+		diag := analysis.Diagnostic{
+			Pos:      expr.Pos(),
+			End:      expr.End(),
+			Category: ruleKeyRegistry,
+			Message:  fmt.Sprintf("key '%s' is not in the key registry", keyValue),
+			Related:  declaredAt(obj),
+		}
+		if suggestion := reg.closest(keyValue); suggestion != "" {
+			diag.Message += fmt.Sprintf(", did you mean '%s'?", suggestion)
+			if key != nil {
+				diag.SuggestedFixes = []analysis.SuggestedFix{{
+					Message: fmt.Sprintf("Rename key to '%s'", suggestion),
+					TextEdits: []analysis.TextEdit{{
+						Pos:     key.Pos(),
+						End:     key.End(),
+						NewText: []byte(quoteLike(key.Value, suggestion)),
+					}},
+				}}
+			}
+		}
+		pass.Report(diag)
+		return
+	}
+	if entry == nil {
+		return
+	}
+
+	if entry.Constructor != "" {
+		// zap.Any and loosely typed pairs are checked by the constructor
+		// that zap picks for the value.
+		got := constructor
+		if (got == "" || got == "zap.Any") && value != nil {
+			got = encoding(pass, value)
+		}
+		if got != "" && got != entry.Constructor {
+			reportf(pass, ruleKeyRegistry, expr, "key '%s' should be logged with %s, got %s", keyValue, entry.Constructor, got)
+		}
+	}
+	if entry.Type != "" && value != nil {
+		if got := typeString(pass, value); got != "" && got != entry.Type {
+			reportf(pass, ruleKeyRegistry, expr, "key '%s' should be logged with a value of type %s, got %s", keyValue, entry.Type, got)
+		}
+	}
+}
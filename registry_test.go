@@ -0,0 +1,126 @@
+package zaplint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadRegistry(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{
+			name:    "valid",
+			content: "keys:\n  user_id:\n    constructor: zap.String\n    description: The ID of the user.\n  latency:\n    type: time.Duration\n  trace_id:\n",
+		},
+		{
+			name:    "constructors without an encoding of their own",
+			content: "keys:\n  error:\n    constructor: zap.Error\n  user:\n    constructor: zap.Object\n  stack:\n    constructor: zap.Stack\n  payload:\n    constructor: zap.Any\n",
+		},
+		{
+			name: "empty",
+		},
+		{
+			name:    "unknown field",
+			content: "keys:\n  user_id:\n    kind: zap.String\n",
+			err:     "line 3: field kind not found",
+		},
+		{
+			name:    "duplicate key",
+			content: "keys:\n  user_id:\n  user_id:\n",
+			err:     `mapping key "user_id" already defined`,
+		},
+		{
+			name:    "unknown constructor",
+			content: "keys:\n  user_id:\n    constructor: zap.String\n  latency:\n    constructor: zap.Latency\n",
+			err:     "registry.yaml:5: constructor=zap.Latency: invalid value",
+		},
+		{
+			name:    "unqualified constructor",
+			content: "keys:\n  user_id:\n    constructor: String\n",
+			err:     "registry.yaml:3: constructor=String: invalid value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "registry.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := loadRegistry(path)
+			switch {
+			case tt.err == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.err != "" && err == nil:
+				t.Fatalf("expected error containing %q", tt.err)
+			case tt.err != "" && !strings.Contains(err.Error(), tt.err):
+				t.Fatalf("error %q does not contain %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestConfigKeyRegistry(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".zaplint.yaml")
+	if err := os.WriteFile(path, []byte("key-registry: logging/keys.yaml\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "logging", "keys.yaml"); cfg.KeyRegistry == nil || *cfg.KeyRegistry != want {
+		t.Fatalf("key-registry resolved to %v, want %s", cfg.KeyRegistry, want)
+	}
+}
+
+func TestClosestKey(t *testing.T) {
+	t.Parallel()
+	reg := &registry{names: []string{"latency", "request_id", "user_id"}}
+	tests := map[string]string{
+		"userID":      "user_id",
+		"user-id":     "user_id",
+		"usr_id":      "user_id",
+		"requestid":   "request_id",
+		"reqest_idd":  "request_id",
+		"latancy":     "latency",
+		"uid":         "",
+		"status":      "",
+		"request_url": "",
+	}
+	for key, want := range tests {
+		if got := reg.closest(key); got != want {
+			t.Errorf("closest(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"userid", "useridentifier", 8},
+		{"héllo", "hello", 1},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package zaplint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const (
+	directivePrefix     = "//zaplint:"
+	directiveIgnore     = "ignore"
+	directiveFileIgnore = "file-ignore"
+
+	// ruleDirective is the category of diagnostics about the directives
+	// themselves, which cannot be suppressed.
+	ruleDirective = "directive"
+)
+
+// rules are the names accepted by suppression directives.
+var rules = []string{ruleCapitalizedMessage, ruleReplaceAny, ruleKeyNamingConvention, ruleKeyTypeConsistency, ruleKeyRegistry, ruleNearDuplicateKeys}
+
+// directive is a suppression comment, either
+//
+//	//zaplint:ignore <rule>[,<rule>...] -- <reason>
+//
+// which covers the line it trails or the statement, declaration or
+// expression that follows it, or
+//
+//	//zaplint:file-ignore <rule>[,<rule>...] -- <reason>
+//
+// which covers the whole file.
+type directive struct {
+	comment    *ast.Comment
+	verb       string
+	rules      []string
+	reason     string    // The text following the rules, including "--".
+	trailing   bool      // Whether the directive trails code on its line.
+	start, end token.Pos // The covered range.
+	used       map[string]bool
+}
+
+// covers reports whether the directive suppresses diag.
+func (d *directive) covers(diag analysis.Diagnostic) bool {
+	return d.start <= diag.Pos && diag.Pos < d.end && slices.Contains(d.rules, diag.Category)
+}
+
+// suppressReports returns a copy of pass that drops the diagnostics covered
+// by the suppression directives in files, along with those directives.
+// Malformed directives are reported.
+func suppressReports(pass *analysis.Pass, files []*ast.File) (*analysis.Pass, []*directive) {
+	var directives []*directive
+	for _, file := range files {
+		directives = append(directives, parseDirectives(pass, file)...)
+	}
+	if len(directives) == 0 {
+		return pass, nil
+	}
+
+	suppress := *pass
+	suppress.Report = func(diag analysis.Diagnostic) {
+		suppressed := false
+		for _, d := range directives {
+			if d.covers(diag) {
+				d.used[diag.Category] = true
+				suppressed = true
+			}
+		}
+		if !suppressed {
+			pass.Report(diag)
+		}
+	}
+	return &suppress, directives
+}
+
+// reportUnusedDirectives reports the rules of directives that did not
+// suppress any diagnostic, suggesting to remove them. Rules that are not
+// enabled are skipped, since nothing can be said about them.
+func reportUnusedDirectives(pass *analysis.Pass, opts *Options, directives []*directive) {
+	for _, d := range directives {
+		var used, unused []string
+		for _, rule := range d.rules {
+			switch {
+			case d.used[rule]:
+				used = append(used, rule)
+			case isEnabled(opts, rule):
+				unused = append(unused, rule)
+			default:
+				// Keep the rule, since it may be needed when enabled.
+				used = append(used, rule)
+			}
+		}
+		if len(unused) == 0 {
+			continue
+		}
+
+		fix := analysis.SuggestedFix{Message: "Remove the unused directive"}
+		if len(used) == 0 {
+			fix.TextEdits = []analysis.TextEdit{removeComment(pass, d)}
+		} else {
+			fix.Message = "Remove the unused rules from the directive"
+			fix.TextEdits = []analysis.TextEdit{{
+				Pos:     d.comment.Pos(),
+				End:     d.comment.End(),
+				NewText: []byte(strings.TrimSpace(directivePrefix + d.verb + " " + strings.Join(used, ",") + " " + d.reason)),
+			}}
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:            d.comment.Pos(),
+			End:            d.comment.End(),
+			Category:       ruleDirective,
+			Message:        fmt.Sprintf("unused zaplint:%s directive for %s", d.verb, strings.Join(unused, ", ")),
+			SuggestedFixes: []analysis.SuggestedFix{fix},
+		})
+	}
+}
+
+// removeComment returns an edit that removes the comment of d, along with
+// the whitespace before it if it trails code, or its whole line otherwise.
+func removeComment(pass *analysis.Pass, d *directive) analysis.TextEdit {
+	tf := pass.Fset.File(d.comment.Pos())
+	edit := analysis.TextEdit{Pos: d.comment.Pos(), End: d.comment.End()}
+	if !d.trailing {
+		line := tf.Line(d.comment.Pos())
+		edit.Pos = tf.LineStart(line)
+		if line < tf.LineCount() {
+			edit.End = tf.LineStart(line + 1)
+		}
+		return edit
+	}
+
+	if pass.ReadFile == nil {
+		return edit
+	}
+	content, err := pass.ReadFile(tf.Name())
+	if err != nil {
+		return edit
+	}
+	offset := tf.Offset(edit.Pos)
+	for offset > 0 && (content[offset-1] == ' ' || content[offset-1] == '\t') {
+		offset--
+	}
+	edit.Pos = tf.Pos(offset)
+	return edit
+}
+
+// isEnabled reports whether rule is checked with opts.
+func isEnabled(opts *Options, rule string) bool {
+	switch rule {
+	case ruleCapitalizedMessage:
+		return opts.CapitalizedMessage
+	case ruleReplaceAny:
+		return opts.ReplaceAny
+	case ruleKeyNamingConvention:
+		return opts.KeyNamingConvention != ""
+	case ruleKeyTypeConsistency:
+		return opts.KeyTypeConsistency
+	case ruleKeyRegistry:
+		return opts.KeyRegistry != ""
+	case ruleNearDuplicateKeys:
+		return opts.NearDuplicateKeys
+	default:
+		return false
+	}
+}
+
+// parseDirectives returns the suppression directives of file.
+func parseDirectives(pass *analysis.Pass, file *ast.File) []*directive {
+	var directives []*directive
+	var lines *lineIndex
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			text, ok := strings.CutPrefix(c.Text, directivePrefix)
+			if !ok {
+				continue
+			}
+
+			d := parseDirective(pass, c, text)
+			if d == nil {
+				continue
+			}
+
+			if lines == nil {
+				lines = newLineIndex(pass.Fset, file)
+			}
+			if d.verb == directiveFileIgnore {
+				d.start, d.end = file.FileStart, file.FileEnd
+			} else {
+				d.start, d.end, d.trailing = lines.coverage(group, c)
+			}
+			directives = append(directives, d)
+		}
+	}
+	return directives
+}
+
+// parseDirective parses the text of a directive following "//zaplint:". It
+// reports malformed directives and returns nil for those that cannot be
+// honored.
+func parseDirective(pass *analysis.Pass, c *ast.Comment, text string) *directive {
+	verb, args, _ := strings.Cut(text, " ")
+	if verb != directiveIgnore && verb != directiveFileIgnore {
+		reportf(pass, ruleDirective, c, "unknown directive zaplint:%s", verb)
+		return nil
+	}
+
+	// The rules are the first field, unless it is already the reason.
+	names, reason, _ := strings.Cut(strings.TrimSpace(args), " ")
+	if strings.HasPrefix(names, "--") {
+		names, reason = "", args
+	}
+	d := &directive{comment: c, verb: verb, reason: reason, used: make(map[string]bool)}
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !slices.Contains(rules, name) {
+			reportf(pass, ruleDirective, c, "unknown rule '%s' in zaplint:%s directive (want one of %s)", name, verb, strings.Join(rules, ", "))
+			continue
+		}
+		d.rules = append(d.rules, name)
+	}
+	if len(d.rules) == 0 {
+		reportf(pass, ruleDirective, c, "zaplint:%s directive should name the rules it suppresses", verb)
+		return nil
+	}
+
+	// The directive is still honored, so that only the missing reason is
+	// reported rather than the diagnostics it was meant to suppress.
+	if reason, ok := strings.CutPrefix(strings.TrimSpace(reason), "--"); !ok || strings.TrimSpace(reason) == "" {
+		reportf(pass, ruleDirective, c, "zaplint:%s directive should explain why after '--'", verb)
+	}
+	return d
+}
+
+// lineIndex records, for each line of a file, the outermost node that starts
+// on it and whether any code precedes a given position on it.
+type lineIndex struct {
+	file  *token.File
+	nodes map[int]ast.Node    // outermost node starting on each line
+	code  map[int][]token.Pos // start and end positions of nodes on each line
+}
+
+func newLineIndex(fset *token.FileSet, file *ast.File) *lineIndex {
+	idx := &lineIndex{
+		file:  fset.File(file.FileStart),
+		nodes: make(map[int]ast.Node),
+		code:  make(map[int][]token.Pos),
+	}
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch node.(type) {
+		case nil, *ast.File, *ast.CommentGroup, *ast.Comment:
+			return node != nil
+		}
+		line := idx.file.Line(node.Pos())
+		if _, ok := idx.nodes[line]; !ok {
+			idx.nodes[line] = node
+		}
+		idx.code[line] = append(idx.code[line], node.Pos())
+		endLine := idx.file.Line(node.End() - 1)
+		idx.code[endLine] = append(idx.code[endLine], node.End()-1)
+		return true
+	})
+	return idx
+}
+
+// coverage returns the range covered by the directive c in group, and
+// whether c trails code. A directive trailing code covers its line and the
+// node starting on it; a directive on a line of its own covers the node
+// starting on the line after its comment group, such as the next statement
+// or a function it documents.
+func (idx *lineIndex) coverage(group *ast.CommentGroup, c *ast.Comment) (token.Pos, token.Pos, bool) {
+	line := idx.file.Line(c.Pos())
+	trailing := slices.ContainsFunc(idx.code[line], func(pos token.Pos) bool { return pos < c.Pos() })
+	if !trailing {
+		line = idx.file.Line(group.End()) + 1
+		if line > idx.file.LineCount() {
+			return token.NoPos, token.NoPos, false
+		}
+	}
+
+	start, end := idx.file.LineStart(line), token.Pos(idx.file.Base()+idx.file.Size())
+	if line < idx.file.LineCount() {
+		end = idx.file.LineStart(line + 1)
+	}
+	if node, ok := idx.nodes[line]; ok {
+		start = min(start, node.Pos())
+		end = max(end, node.End())
+	}
+	return start, end, trailing
+}
@@ -1,6 +1,9 @@
 package capitalized
 
-import "go.uber.org/zap"
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
 
 func tests() {
 	logger, _ := zap.NewProduction()
@@ -21,4 +24,23 @@ func tests() {
 	logger.DPanic("dpanic message should be capitalized") // want "message 'dpanic message should be capitalized' should be capitalized"
 	logger.Panic("panic message should be capitalized")   // want "message 'panic message should be capitalized' should be capitalized"
 	logger.Fatal("fatal message should be capitalized")   // want "message 'fatal message should be capitalized' should be capitalized"
+
+	// Unicode, raw strings and escape sequences
+	logger.Info("Élan message should be capitalized")
+	logger.Info("ǅungla message should be capitalized")
+	logger.Info("élan message should be capitalized")          // want "message 'élan message should be capitalized' should be capitalized"
+	logger.Info("ǆungla message should be capitalized")        // want "message 'ǆungla message should be capitalized' should be capitalized"
+	logger.Info(`raw message should be capitalized`)           // want "message 'raw message should be capitalized' should be capitalized"
+	logger.Info("\u00e9t\u00e9 message should be capitalized") // want "message 'été message should be capitalized' should be capitalized"
+	logger.Info("\x61scii message should\tbe capitalized")     // want "message 'ascii message should\\tbe capitalized' should be capitalized"
+	logger.Info("123 message cannot be capitalized")           // want "message '123 message cannot be capitalized' should be capitalized"
+
+	// SugaredLogger messages and templates
+	sugar := logger.Sugar()
+	sugar.Info("Sugared message should be capitalized")
+	sugar.Infow("sugared message should be capitalized", "key", "value")   // want "message 'sugared message should be capitalized' should be capitalized"
+	sugar.Errorf("sugared template %s should be capitalized", "x")         // want "message 'sugared template %s should be capitalized' should be capitalized"
+	sugar.Warn("sugared message should be capitalized")                    // want "message 'sugared message should be capitalized' should be capitalized"
+	sugar.Logw(zapcore.InfoLevel, "sugared message should be capitalized") // want "message 'sugared message should be capitalized' should be capitalized"
+	logger.Log(zapcore.InfoLevel, "leveled message should be capitalized") // want "message 'leveled message should be capitalized' should be capitalized"
 }
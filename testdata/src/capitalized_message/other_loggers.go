@@ -0,0 +1,17 @@
+package capitalized
+
+import "testing"
+
+type reporter struct{}
+
+func (reporter) Info(msg string)  {}
+func (reporter) Error(msg string) {}
+
+func otherLoggers(t *testing.T) {
+	// Methods with the same names on other types are not zap messages
+	var r reporter
+	r.Info("message from a custom reporter")
+	r.Error("error from a custom reporter")
+	t.Error("error from a test")
+	t.Log("log from a test")
+}
@@ -0,0 +1,9 @@
+package cascade
+
+import "go.uber.org/zap"
+
+func tests() {
+	logger, _ := zap.NewProduction()
+	logger.Info("Message", zap.String("user_name", "test"))
+	logger.Info("message", zap.String("userName", "test")) // want "message 'message' should be capitalized" "key 'userName' should be in snake_case"
+}
@@ -0,0 +1,9 @@
+package isolated
+
+import "go.uber.org/zap"
+
+func tests() {
+	logger, _ := zap.NewProduction()
+	logger.Info("message", zap.String("userName", "test"))
+	logger.Info("message", zap.Any("user_name", "test")) // want "replace zap.Any with zap.String"
+}
@@ -0,0 +1,9 @@
+package legacy
+
+import "go.uber.org/zap"
+
+func excludedTests() {
+	logger, _ := zap.NewProduction()
+	// This file is excluded by the parent configuration file
+	logger.Info("message", zap.String("user_name", "test"))
+}
@@ -0,0 +1,9 @@
+package legacy
+
+import "go.uber.org/zap"
+
+func tests() {
+	logger, _ := zap.NewProduction()
+	logger.Info("Message", zap.String("userName", "test"))
+	logger.Info("message", zap.String("user_name", "test")) // want "message 'message' should be capitalized" "key 'user_name' should be in camelCase"
+}
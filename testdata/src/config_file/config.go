@@ -0,0 +1,15 @@
+package config
+
+import "go.uber.org/zap"
+
+func tests() {
+	logger, _ := zap.NewProduction()
+
+	// Positive cases - should pass
+	logger.Info("Message", zap.String("user-name", "test"))
+
+	// Negative cases - should trigger lint errors
+	logger.Info("message")                                  // want "message 'message' should be capitalized"
+	logger.Info("Message", zap.String("user_name", "test")) // want "key 'user_name' should be in kebab-case"
+	logger.Info("Message", zap.Any("user-name", "test"))    // want "replace zap.Any with zap.String"
+}
@@ -0,0 +1,9 @@
+package config
+
+import "go.uber.org/zap"
+
+func excludedTests() {
+	logger, _ := zap.NewProduction()
+	// This file is excluded by the configuration file
+	logger.Info("message", zap.String("user_name", "test"))
+}
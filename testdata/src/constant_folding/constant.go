@@ -0,0 +1,47 @@
+package constant
+
+import (
+	"go.uber.org/zap"
+
+	"constant_folding/keys"
+)
+
+const (
+	keyUserName = "user_name"
+	keyUserID   = "userID"
+	keyRetry    = ("retryCount")
+	keyPrefix   = "user"
+	keyJoined   = keyPrefix + "Name"
+
+	msgStarted = "Service started"
+	msgStopped = "service stopped"
+)
+
+type key string
+
+const keyTyped key = "isValid"
+
+func tests() {
+	logger, _ := zap.NewProduction()
+
+	// Positive cases - should pass
+	logger.Info(msgStarted, zap.String(keyUserName, "test"))
+	logger.Info("Message", zap.String(keys.RequestID, "123"))
+	logger.Info("Message", zap.String("user"+"_"+"name", "test"))
+
+	// Negative cases - reported where they are used, without fixes
+	logger.Info("Message", zap.String(keyUserID, "1"))       // want "key 'userID' should be in snake_case"
+	logger.Info("Message", zap.Int(keyUserID, 1))            // want "key 'userID' should be in snake_case"
+	logger.Info("Message", zap.Int(keyRetry, 1))             // want "key 'retryCount' should be in snake_case"
+	logger.Info("Message", zap.String(keyJoined, "test"))    // want "key 'userName' should be in snake_case"
+	logger.Info("Message", zap.Bool(string(keyTyped), true)) // want "key 'isValid' should be in snake_case"
+	logger.Info(msgStopped)                                  // want "message 'service stopped' should be capitalized"
+	logger.Sugar().Infow(msgStopped, keyUserID, "1")         // want "message 'service stopped' should be capitalized" "key 'userID' should be in snake_case"
+	logger.Info("Message", zap.String(keyUserID, "1"))       //zaplint:ignore key-naming-convention -- the key is fixed by an external dashboard
+
+	// Negative cases - reported where they are used
+	logger.Info("Message", zap.String("user"+"Name", "test")) // want "key 'userName' should be in snake_case"
+	logger.Info("Message", zap.String(keys.UserID, "1"))      // want "key 'userID' should be in snake_case"
+	logger.Info(keys.Message)                                 // want "message 'imported message' should be capitalized"
+	logger.Info("service " + "stopped")                       // want "message 'service stopped' should be capitalized"
+}
@@ -0,0 +1,7 @@
+package keys
+
+const (
+	RequestID = "request_id"
+	UserID    = "userID"
+	Message   = "imported message"
+)
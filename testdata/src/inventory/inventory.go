@@ -0,0 +1,39 @@
+package inventory
+
+import (
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const msgStarted = "Server started"
+
+type Server struct {
+	logger *zap.Logger
+	sugar  *zap.SugaredLogger
+}
+
+func (s *Server) Start(addr string, timeout time.Duration) {
+	s.logger.Info(msgStarted, zap.String("addr", addr), zap.Duration("timeout", timeout))
+	s.logger.Error("Start failed", zap.Error(errors.New("closed")), zap.Any("attempt", 3))
+	s.logger.Log(zapcore.WarnLevel, "Slow start", zap.Int64("elapsed_ms", 1))
+}
+
+func (s Server) Stop(reason string, fields []zap.Field) {
+	s.sugar.Infow("Server stopped", "reason", reason, zap.Bool("graceful", true), "code", 0)
+	s.sugar.Warnf("Stopping %s", reason)
+	s.logger.Debug(reason, fields...)
+}
+
+func helper(logger *zap.Logger, lvl zapcore.Level, key string) {
+	logger.Log(lvl, "Dynamic level", zap.String(key, "value"), zap.Inline(nil))
+	if ce := logger.Check(zap.DebugLevel, "Checked"); ce != nil {
+		ce.Write()
+	}
+	func() {
+		logger.Sugar().Errorw("In closure", "count", 1)
+	}()
+	logger.With(zap.String("ignored", "")).Named("ignored")
+}
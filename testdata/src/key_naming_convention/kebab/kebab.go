@@ -35,4 +35,10 @@ func tests() {
 	logger.Info("message", zap.String("API-Version", "v3"))  // want "key 'API-Version' should be in kebab-case"
 	logger.Info("message", zap.String("HTTPResponse", "ok")) // want "key 'HTTPResponse' should be in kebab-case"
 	logger.Info("message", zap.Int("MAX_RETRY", 10))         // want "key 'MAX_RETRY' should be in kebab-case"
+
+	// Acronyms, digits, mixed separators and raw strings
+	logger.Info("message", zap.String("HTTP2Server", "test"))  // want "key 'HTTP2Server' should be in kebab-case"
+	logger.Info("message", zap.String("user-Name_ID", "test")) // want "key 'user-Name_ID' should be in kebab-case"
+	logger.Info("message", zap.String(`RequestURL`, "test"))   // want "key 'RequestURL' should be in kebab-case"
+	logger.Info("message", zap.String("ipv4Address", "test"))  // want "key 'ipv4Address' should be in kebab-case"
 }
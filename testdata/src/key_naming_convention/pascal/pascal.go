@@ -35,4 +35,10 @@ func tests() {
 	logger.Info("message", zap.String("apiVersion", "v3"))   // want "key 'apiVersion' should be in PascalCase"
 	logger.Info("message", zap.String("httpResponse", "ok")) // want "key 'httpResponse' should be in PascalCase"
 	logger.Info("message", zap.Int("maxRetry", 10))          // want "key 'maxRetry' should be in PascalCase"
+
+	// Acronyms, digits, mixed separators and raw strings
+	logger.Info("message", zap.String("http2Server", "test"))  // want "key 'http2Server' should be in PascalCase"
+	logger.Info("message", zap.String("user-Name_ID", "test")) // want "key 'user-Name_ID' should be in PascalCase"
+	logger.Info("message", zap.String(`requestURL`, "test"))   // want "key 'requestURL' should be in PascalCase"
+	logger.Info("message", zap.String("ipv4Address", "test"))  // want "key 'ipv4Address' should be in PascalCase"
 }
@@ -0,0 +1,38 @@
+package registry
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const sessionKey = "session"
+
+// Constants are reported, but not renamed, since they may have other uses.
+const traceKey = "traceID"
+
+func tests(logger *zap.Logger, sugar *zap.SugaredLogger, id int64, err error, user zapcore.ObjectMarshaler) {
+	// Negative cases - should trigger lint errors
+	logger.Info("Request", zap.String("userID", "a"))                    // want `key 'userID' is not in the key registry, did you mean 'user_id'\?`
+	logger.Info("Request", zap.String("requst_id", "a"))                 // want `key 'requst_id' is not in the key registry, did you mean 'request_id'\?`
+	logger.Info("Request", zap.String("status", "ok"))                   // want `key 'status' is not in the key registry`
+	logger.Info("Request", zap.String(sessionKey, "a"))                  // want `key 'session' is not in the key registry`
+	logger.Info("Request", zap.String(traceKey, "a"))                    // want `key 'traceID' is not in the key registry, did you mean 'trace_id'\?`
+	logger.Info("Request", zap.Int64("user_id", id))                     // want `key 'user_id' should be logged with zap.String, got zap.Int64`
+	logger.Info("Request", zap.Any("request_id", "a"))                   // want `key 'request_id' should be logged with zap.Int64, got zap.String`
+	logger.Info("Request", zap.Int64("latency", 5))                      // want `key 'latency' should be logged with a value of type time.Duration, got int64`
+	sugar.Infow("Request", "user_id", 1, "userid", "a", "latency", "1s") // want `key 'user_id' should be logged with zap.String, got zap.Int` `key 'userid' is not in the key registry, did you mean 'user_id'\?` `key 'latency' should be logged with a value of type time.Duration, got string`
+
+	logger.Info("Request", zap.Object("account", user)) // want `key 'account' is not in the key registry`
+	logger.Info("Request", zap.Stack("stack"))          // want `key 'stack' is not in the key registry`
+	logger.Info("Request", zap.String("error", "a"))    // want `key 'error' should be logged with zap.Error, got zap.String`
+
+	// Positive cases - should not trigger lint errors
+	logger.Info("Request", zap.String("user_id", "a"))
+	logger.Info("Request", zap.Any("request_id", id))
+	logger.Info("Request", zap.Duration("latency", time.Second))
+	logger.Info("Request", zap.Int("trace_id", 1))
+	logger.Info("Request", zap.Error(err), zap.Object("user", user))
+	sugar.Infow("Request", "user_id", "a", "trace_id", 1.5, zap.Int64("request_id", id))
+}
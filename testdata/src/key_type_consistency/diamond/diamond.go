@@ -0,0 +1,16 @@
+package diamond // want package:"keyTypes\\(user_id:zap.Int64, user_id:zap.String\\)"
+
+import (
+	"go.uber.org/zap"
+
+	"key_type_consistency/siblings"
+	"key_type_consistency/siblings/a"
+	"key_type_consistency/siblings/b"
+)
+
+// The conflict between a and b is reported in siblings only.
+func Order(logger *zap.Logger) {
+	siblings.Checkout(logger)
+	a.Login(logger, "1")
+	b.Pay(logger, 1)
+}
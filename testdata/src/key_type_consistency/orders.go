@@ -0,0 +1,26 @@
+package orders // want package:"keyTypes\\(attempts:zap.Int, attempts:zap.Strings, order_id:zap.String, paid_at:zap.String, paid_at:zap.Time, user_id:zap.Int64, user_id:zap.String\\)"
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"key_type_consistency/users"
+)
+
+func Place(logger *zap.Logger, userID int64) {
+	users.Login(logger, "admin")
+
+	// Negative cases - should trigger lint errors
+	logger.Info("order placed", zap.Int64("user_id", userID)) // want `key 'user_id' is logged as zap.Int64, but as zap.String in key_type_consistency/users`
+	logger.Sugar().Infow("order shipped", "user_id", userID)  // want `key 'user_id' is logged as zap.Int64, but as zap.String in key_type_consistency/users`
+	logger.Info("order paid", zap.String("paid_at", "now"))
+	logger.Info("order delivered", zap.Time("paid_at", time.Now()))     // want `key 'paid_at' is logged as zap.Time, but as zap.String elsewhere in this package`
+	logger.Info("order retried", zap.Strings("attempts", []string{""})) // want `key 'attempts' is logged as zap.Strings, but as zap.Int in key_type_consistency/users`
+
+	// Positive cases - should not trigger lint errors
+	logger.Info("order created", zap.String("order_id", "1"))
+	logger.Info("order updated", zap.Stringer("order_id", time.Second))
+	logger.Info("order refunded", zap.Reflect("order_id", userID))
+	logger.Sugar().Infow("order viewed", "order_id", "2")
+}
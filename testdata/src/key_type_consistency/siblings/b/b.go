@@ -0,0 +1,7 @@
+package b
+
+import "go.uber.org/zap"
+
+func Pay(logger *zap.Logger, id int64) {
+	logger.Info("user paid", zap.Int64("user_id", id))
+}
@@ -0,0 +1,13 @@
+package siblings // want package:"keyTypes\\(user_id:zap.Int64, user_id:zap.String\\)"
+
+import (
+	"go.uber.org/zap"
+
+	"key_type_consistency/siblings/a"
+	"key_type_consistency/siblings/b" // want `key 'user_id' is logged as zap.Int64 in key_type_consistency/siblings/b, but as zap.String in key_type_consistency/siblings/a`
+)
+
+func Checkout(logger *zap.Logger) {
+	a.Login(logger, "1")
+	b.Pay(logger, 1)
+}
@@ -0,0 +1,7 @@
+package users
+
+import "go.uber.org/zap"
+
+func Login(logger *zap.Logger, id string) {
+	logger.Info("user logged in", zap.String("user_id", id), zap.Int("attempts", 1))
+}
@@ -0,0 +1,16 @@
+package diamond // want package:"keyCounts\\(\\)"
+
+import (
+	"go.uber.org/zap"
+
+	"near_duplicate_keys/siblings"
+	"near_duplicate_keys/siblings/a"
+	"near_duplicate_keys/siblings/b"
+)
+
+// The near-duplicates in a and b are reported in siblings only.
+func Order(logger *zap.Logger) {
+	siblings.Checkout(logger, "1")
+	a.Login(logger, "1")
+	b.Pay(logger, "1")
+}
@@ -0,0 +1,21 @@
+package distance // want package:"keyCounts\\(id:1, ip:1, requst_id:1, user_id:1, userid:1, usr_id:2\\)"
+
+import (
+	"go.uber.org/zap"
+
+	"near_duplicate_keys/users"
+)
+
+func tests(logger *zap.Logger) {
+	users.Login(logger, "admin")
+
+	// Negative cases - should trigger lint errors
+	logger.Info("order placed", zap.String("usr_id", "a"))  // want `key 'usr_id' \(2 uses\) is a near-duplicate of 'user_id' \(4 uses\), 'userid' \(1 use\); use 'user_id', the most common spelling`
+	logger.Info("order paid", zap.String("usr_id", "a"))    // want `key 'usr_id' \(2 uses\)`
+	logger.Info("order shipped", zap.String("userid", "a")) // want `key 'userid' \(1 use\) is a near-duplicate of 'user_id' \(4 uses\), 'usr_id' \(2 uses\); use 'user_id'`
+	logger.Info("order viewed", zap.String("requst_id", "a"))
+
+	// Positive cases - should not trigger lint errors
+	logger.Info("order updated", zap.String("user_id", "a"))
+	logger.Info("order cancelled", zap.String("id", "a"), zap.String("ip", "b"))
+}
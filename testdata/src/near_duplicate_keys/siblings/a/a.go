@@ -0,0 +1,8 @@
+package a
+
+import "go.uber.org/zap"
+
+func Login(logger *zap.Logger, id string) {
+	logger.Info("user logged in", zap.String("user_id", id))
+	logger.Info("user logged out", zap.String("user_id", id))
+}
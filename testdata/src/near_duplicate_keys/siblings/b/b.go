@@ -0,0 +1,7 @@
+package b
+
+import "go.uber.org/zap"
+
+func Pay(logger *zap.Logger, id string) {
+	logger.Info("user paid", zap.String("userId", id))
+}
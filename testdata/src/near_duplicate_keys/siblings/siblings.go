@@ -0,0 +1,14 @@
+package siblings // want package:"keyCounts\\(order_id:1\\)"
+
+import (
+	"go.uber.org/zap"
+
+	"near_duplicate_keys/siblings/a"
+	"near_duplicate_keys/siblings/b" // want `key 'userId' \(1 use\) in near_duplicate_keys/siblings/b is a near-duplicate of 'user_id' \(2 uses\); use 'user_id', the most common spelling`
+)
+
+func Checkout(logger *zap.Logger, id string) {
+	a.Login(logger, id)
+	b.Pay(logger, id)
+	logger.Info("order placed", zap.String("order_id", "1"))
+}
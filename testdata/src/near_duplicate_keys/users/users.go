@@ -0,0 +1,9 @@
+package users
+
+import "go.uber.org/zap"
+
+func Login(logger *zap.Logger, id string) {
+	logger.Info("user logged in", zap.String("user_id", id))
+	logger.Info("user logged out", zap.String("user_id", id))
+	logger.Sugar().Infow("user renamed", "user_id", id, "user_name", "bob")
+}
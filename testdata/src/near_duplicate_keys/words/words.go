@@ -0,0 +1,24 @@
+package words // want package:"keyCounts\\(USER_ID:1, UserName:1, Value:1, requestId:1, request_id:1, userId:2, user_identifier:1, userid:1, value:2\\)"
+
+import (
+	"go.uber.org/zap"
+
+	"near_duplicate_keys/users"
+)
+
+func tests(logger *zap.Logger) {
+	users.Login(logger, "admin")
+
+	// Negative cases - should trigger lint errors
+	logger.Info("order placed", zap.String("userId", "a"))                          // want `key 'userId' \(2 uses\) is a near-duplicate of 'user_id' \(3 uses\), 'USER_ID' \(1 use\), 'userid' \(1 use\); use 'user_id', the most common spelling`
+	logger.Info("order paid", zap.String("userId", "a"), zap.String("userid", "a")) // want `key 'userId' \(2 uses\)` `key 'userid' \(1 use\) is a near-duplicate of 'user_id' \(3 uses\), 'userId' \(2 uses\), 'USER_ID' \(1 use\); use 'user_id'`
+	logger.Sugar().Infow("order shipped", "USER_ID", "a", "UserName", "b")          // want `key 'USER_ID' \(1 use\)` `key 'UserName' \(1 use\) is a near-duplicate of 'user_name' \(1 use\); use 'user_name', the most common spelling`
+	logger.Info("order viewed", zap.String("requestId", "a"))                       // want `key 'requestId' \(1 use\) is a near-duplicate of 'request_id' \(1 use\); use 'request_id', the most common spelling`
+	logger.Info("order rated", zap.Int("Value", 1))                                 // want `key 'Value' \(1 use\) is a near-duplicate of 'value' \(2 uses\); use 'value', the most common spelling`
+
+	// Positive cases - should not trigger lint errors
+	logger.Info("order cancelled", zap.String("request_id", "a"))
+	logger.Info("order refunded", zap.String("user_identifier", "a"))
+	logger.Info("order rated", zap.Int("value", 1))
+	logger.Info("order rerated", zap.Int("value", 2)) // zap logs 'value' too, which is not counted
+}
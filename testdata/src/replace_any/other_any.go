@@ -0,0 +1,12 @@
+package replace_any
+
+type registry struct{}
+
+func (registry) Any(key string, value any) bool { return false }
+
+func otherAny() {
+	// Any functions outside of zap are left alone
+	var r registry
+	r.Any("user_name", "test")
+	r.Any("request_id", 123)
+}
@@ -0,0 +1,17 @@
+package replace_any
+
+import (
+	"time"
+
+	z "go.uber.org/zap"
+)
+
+func aliasTests() {
+	logger, _ := z.NewProduction()
+
+	// Aliased imports keep their alias in the suggested fix
+	logger.Info("message", z.Any("user_name", "test"))          // want "replace zap.Any with zap.String"
+	logger.Info("message", z.Any("request_id", 123))            // want "replace zap.Any with zap.Int"
+	logger.Info("message", z.Any("process_time", time.Second))  // want "replace zap.Any with zap.Duration"
+	logger.Info("message", z.Any("int_array", [3]int{1, 2, 3})) // want "replace zap.Any with zap.Ints"
+}
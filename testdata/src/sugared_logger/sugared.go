@@ -0,0 +1,40 @@
+package sugared
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type customKey string
+
+func tests(dynamicKey string, args []any) {
+	logger, _ := zap.NewProduction()
+	sugar := logger.Sugar()
+
+	// Positive cases - should pass
+	sugar.Infow("message", "user_name", "test", "request_id", 123)
+	sugar.Debugw("message", "user_name", "test")
+	sugar.Warnw("message", "user_name", "test")
+	sugar.Errorw("message", "user_name", "test")
+	sugar.Logw(zapcore.InfoLevel, "message", "user_name", "test")
+	sugar.With("user_name", "test").Info("message")
+	sugar.WithLazy("user_name", "test").Info("message")
+	sugar.Infow("message", zap.String("user_name", "test"), "request_id", 123)
+	sugar.Infow("message", args...)
+	sugar.Infof("%s message", "formatted")
+
+	// Negative cases - should trigger lint errors
+	sugar.Infow("message", "userName", "test")                           // want "key 'userName' should be in snake_case"
+	sugar.Errorw("message", "user_name", "test", "RequestID", 123)       // want "key 'RequestID' should be in snake_case"
+	sugar.Logw(zapcore.InfoLevel, "message", "isValid", true)            // want "key 'isValid' should be in snake_case"
+	sugar.With("apiVersion", "v2").Info("message")                       // want "key 'apiVersion' should be in snake_case"
+	sugar.WithLazy("retryCount", 5).Info("message")                      // want "key 'retryCount' should be in snake_case"
+	sugar.Infow("message", zap.String("userName", "test"), "isValid", 1) // want "key 'userName' should be in snake_case" "key 'isValid' should be in snake_case"
+
+	// Malformed key-value pairs
+	sugar.Infow("message", "user_name", "test", "request_id") // want "odd number of arguments passed as key-value pairs"
+	sugar.With("user_name")                                   // want "odd number of arguments passed as key-value pairs"
+	sugar.Infow("message", 42, "test")                        // want "key should be a string, got int"
+	sugar.Infow("message", customKey("user_name"), "test")    // want "key should be a string, got customKey"
+	sugar.Infow("message", dynamicKey, "test")                // want "key should be a constant string"
+}
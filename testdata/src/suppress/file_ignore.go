@@ -0,0 +1,11 @@
+//zaplint:file-ignore replace-any -- generated from the legacy schema
+
+package suppress
+
+import "go.uber.org/zap"
+
+func fileIgnored() {
+	logger, _ := zap.NewProduction()
+	logger.Info("Message", zap.Any("user_name", "test"))
+	logger.Info("message", zap.Any("user_name", "test")) // want "message 'message' should be capitalized"
+}
@@ -0,0 +1,51 @@
+package suppress
+
+import "go.uber.org/zap"
+
+func tests() {
+	logger, _ := zap.NewProduction()
+
+	// Trailing directives cover their line
+	logger.Info("message")                                 //zaplint:ignore capitalized-message -- matches an existing alert
+	logger.Info("Message", zap.String("userName", "test")) //zaplint:ignore key-naming-convention -- legacy key
+	logger.Info("message", zap.String("userName", "test")) //zaplint:ignore key-naming-convention,capitalized-message -- legacy
+
+	// Directives only suppress the rules they name
+	logger.Info("message", zap.String("userName", "test")) //zaplint:ignore key-naming-convention -- legacy key // want "message 'message' should be capitalized"
+
+	// Directives on a line of their own cover the next statement
+	//zaplint:ignore replace-any -- the type is decided at runtime
+	logger.Info("Message",
+		zap.Any("user_name", "test"),
+		zap.Any("request_id", 123),
+	)
+	logger.Info("Message", zap.Any("user_name", "test")) // want "replace zap.Any with zap.String"
+
+	// Directives inside a call cover the next argument
+	logger.Info("Message",
+		//zaplint:ignore key-naming-convention -- legacy key
+		zap.String("userName", "test"),
+		zap.String("requestID", "test"), // want "key 'requestID' should be in snake_case"
+	)
+}
+
+// legacy is kept as is until the service is retired.
+//
+//zaplint:ignore capitalized-message,key-naming-convention -- retired soon
+func legacy() {
+	logger, _ := zap.NewProduction()
+	logger.Info("message", zap.String("userName", "test"))
+	logger.Warn("message", zap.String("requestID", "test"))
+}
+
+func malformed() {
+	logger, _ := zap.NewProduction()
+
+	// Directives without a reason are reported but still honored
+	logger.Info("message") //zaplint:ignore capitalized-message // want "zaplint:ignore directive should explain why after '--'"
+
+	// Directives with unknown rules or verbs are reported
+	logger.Info("Message") //zaplint:ignore capitalised-message -- typo // want "unknown rule 'capitalised-message' in zaplint:ignore directive" "zaplint:ignore directive should name the rules it suppresses"
+	logger.Info("Message") //zaplint:ignore -- no rule // want "zaplint:ignore directive should name the rules it suppresses"
+	logger.Info("Message") //zaplint:disable capitalized-message -- typo // want "unknown directive zaplint:disable"
+}
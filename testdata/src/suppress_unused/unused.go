@@ -0,0 +1,22 @@
+package unused
+
+import "go.uber.org/zap"
+
+func tests() {
+	logger, _ := zap.NewProduction()
+
+	// Used directives are not reported
+	logger.Info("message") //zaplint:ignore capitalized-message -- matches an existing alert
+
+	// Directives for disabled rules are not reported
+	logger.Info("Message", zap.Any("user_name", "test")) //zaplint:ignore replace-any -- decided at runtime
+
+	// Unused directives are reported
+	logger.Info("Message") //zaplint:ignore capitalized-message -- fixed since // want "unused zaplint:ignore directive for capitalized-message"
+
+	//zaplint:ignore key-naming-convention -- fixed since // want "unused zaplint:ignore directive for key-naming-convention"
+	logger.Info("Message", zap.String("user_name", "test"))
+
+	// Unused rules are removed from directives
+	logger.Info("message", zap.String("user_name", "test")) //zaplint:ignore key-naming-convention,capitalized-message -- legacy // want "unused zaplint:ignore directive for key-naming-convention"
+}
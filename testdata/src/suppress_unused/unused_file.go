@@ -0,0 +1,10 @@
+//zaplint:file-ignore key-naming-convention -- fixed since // want "unused zaplint:file-ignore directive for key-naming-convention"
+
+package unused
+
+import "go.uber.org/zap"
+
+func fileTests() {
+	logger, _ := zap.NewProduction()
+	logger.Info("Message", zap.String("user_name", "test"))
+}
@@ -5,11 +5,17 @@ import (
 	"flag"
 	"fmt"
 	"go/ast"
+	"go/build"
+	"go/constant"
 	"go/token"
 	"go/types"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/inspect"
@@ -24,14 +30,59 @@ const (
 	PascalCase = "pascal"
 )
 
+// Rule names, used as diagnostic categories and in suppression directives.
+const (
+	ruleCapitalizedMessage  = "capitalized-message"
+	ruleReplaceAny          = "replace-any"
+	ruleKeyNamingConvention = "key-naming-convention"
+	ruleKeyTypeConsistency  = "key-type-consistency"
+	ruleKeyRegistry         = "key-registry"
+	ruleNearDuplicateKeys   = "near-duplicate-keys"
+)
+
 var errInvalidValue = errors.New("invalid value")
 
 // Options are options for the zaplint analyzer.
+//
+// Settings that are left unset are taken from the configuration file, if
+// any, so that options given on the command line take precedence over it.
+// A setting is unset if it has its zero value and its flag was neither
+// given nor marked with MarkExplicit: an explicit -capitalized-message=false
+// turns off a rule enabled in the file.
 type Options struct {
-	CapitalizedMessage  bool     // Enforce capitalized message.
-	ReplaceAny          bool     // Enforce replacing zap.Any with the appropriate type.
-	KeyNamingConvention string   // Enforce a single key naming convention ("snake", "kebab", "camel", or "pascal").
-	ExcludeFiles        []string // Exclude files matching the given patterns.
+	CapitalizedMessage       bool     // Enforce capitalized message.
+	ReplaceAny               bool     // Enforce replacing zap.Any with the appropriate type.
+	KeyNamingConvention      string   // Enforce a single key naming convention ("snake", "kebab", "camel", or "pascal").
+	KeyTypeConsistency       bool     // Enforce logging each key with the same type across packages.
+	KeyRegistry              string   // Path to a key registry file listing the keys that may be logged.
+	NearDuplicateKeys        bool     // Report keys spelled like a more common key, across packages.
+	NearDuplicateKeyDistance int      // The edit distance up to which keys are near-duplicates; if 0, only keys of the same words are.
+	ExcludeFiles             []string // Exclude files matching the given patterns.
+	Config                   string   // Path to a configuration file; if empty, .zaplint.yaml is searched for upward from each package directory to the repository root.
+
+	ReportUnusedDirectives bool // Report suppression directives that do not suppress any diagnostic.
+
+	explicit map[string]bool // The names of the flags that were given.
+}
+
+// MarkExplicit marks the settings of the given flag names as given
+// explicitly, so that the configuration file does not override them even
+// when they have their zero value. The analyzer's flags mark the settings
+// they set; MarkExplicit is for Options populated otherwise, such as from
+// the settings of the golangci-lint plugin.
+func (opts *Options) MarkExplicit(names ...string) {
+	if opts.explicit == nil {
+		opts.explicit = make(map[string]bool)
+	}
+	for _, name := range names {
+		opts.explicit[name] = true
+	}
+}
+
+// unset reports whether the setting of the flag name takes its value from
+// the configuration file, given whether it has its zero value.
+func (opts *Options) unset(name string, zero bool) bool {
+	return zero && !opts.explicit[name]
 }
 
 // New creates a new zaplint analyzer.
@@ -39,35 +90,126 @@ func New(opts *Options) *analysis.Analyzer {
 	if opts == nil {
 		opts = &Options{}
 	}
+	loader := newConfigLoader()
 
-	return &analysis.Analyzer{
+	a := &analysis.Analyzer{
 		Name:     "zaplint",
 		Doc:      "ensure consistent code style when using zap",
-		Flags:    flags(opts),
+		URL:      "https://github.com/rleungx/zaplint#rules",
 		Requires: []*analysis.Analyzer{inspect.Analyzer},
 		Run: func(pass *analysis.Pass) (any, error) {
-			switch opts.KeyNamingConvention {
-			case "", SnakeCase, KebabCase, CamelCase, PascalCase:
-			default:
-				return nil, fmt.Errorf("zaplint: Options.KeyNamingConvention=%s: %w", opts.KeyNamingConvention, errInvalidValue)
+			// The packages of the standard library and of other modules are
+			// only analyzed when facts are declared. They are neither
+			// configured nor checked, and export no facts, so that the keys
+			// they log are not compared with those of the module.
+			if !inMainModule(pass) {
+				return nil, nil
+			}
+
+			opts, err := loader.resolve(pass, opts)
+			if err != nil {
+				return nil, err
+			}
+
+			regexps, err := validate(opts)
+			if err != nil {
+				return nil, err
 			}
 
-			var regexps []*regexp.Regexp
-			for _, pattern := range opts.ExcludeFiles {
-				re, err := regexp.Compile(pattern)
-				if err != nil {
-					return nil, fmt.Errorf("zaplint: Options.ExcludeFiles=%s: %w", pattern, err)
+			var reg *registry
+			if opts.KeyRegistry != "" {
+				if reg, err = loader.keyRegistry(opts.KeyRegistry); err != nil {
+					return nil, err
 				}
-				regexps = append(regexps, re)
 			}
-			run(pass, opts, regexps)
+			run(pass, opts, regexps, reg)
 			return nil, nil
 		},
 	}
+	a.Flags = flags(opts, func() { a.FactTypes = factTypes(loader, opts) })
+	a.FactTypes = factTypes(loader, opts)
+	return a
+}
+
+// factTypes returns the fact types of the analyzer given opts. Facts make
+// drivers analyze every dependency from source, so they are only declared
+// when a rule that compares keys across packages is enabled by opts or by
+// the configuration files that apply to the current directory. Otherwise,
+// those rules only compare the keys of each package.
+func factTypes(loader *configLoader, opts *Options) []analysis.Fact {
+	resolved := opts
+	if dir, err := os.Getwd(); err == nil {
+		// Errors are reported when the packages are analyzed.
+		if r, err := loader.resolveDir(dir, opts); err == nil {
+			resolved = r
+		}
+	}
+	if !resolved.KeyTypeConsistency && !resolved.NearDuplicateKeys {
+		return nil
+	}
+	return []analysis.Fact{(*keyTypesFact)(nil), (*keyCountsFact)(nil)}
 }
 
-func flags(opts *Options) flag.FlagSet {
+// inMainModule reports whether the package of pass belongs to the module
+// being analyzed, rather than to the standard library or a dependency.
+// Without module information, as in GOPATH mode, the packages in GOROOT,
+// the module cache or a vendor directory are dependencies.
+func inMainModule(pass *analysis.Pass) bool {
+	if pass.Module != nil && pass.Module.Path != "" {
+		return pass.Module.Version == ""
+	}
+	if len(pass.Files) == 0 {
+		return false
+	}
+	filename := filepath.ToSlash(pass.Fset.Position(pass.Files[0].Package).Filename)
+	if strings.Contains(filename, "/vendor/") {
+		return false
+	}
+	modcache := os.Getenv("GOMODCACHE")
+	if gopath := filepath.SplitList(build.Default.GOPATH); modcache == "" && len(gopath) > 0 {
+		modcache = filepath.Join(gopath[0], "pkg", "mod")
+	}
+	for _, dir := range []string{build.Default.GOROOT, modcache} {
+		if dir != "" && strings.HasPrefix(filename, filepath.ToSlash(dir)+"/") {
+			return false
+		}
+	}
+	return true
+}
+
+// validate checks opts and compiles its ExcludeFiles patterns.
+func validate(opts *Options) ([]*regexp.Regexp, error) {
+	switch opts.KeyNamingConvention {
+	case "", SnakeCase, KebabCase, CamelCase, PascalCase:
+	default:
+		return nil, fmt.Errorf("zaplint: Options.KeyNamingConvention=%s: %w", opts.KeyNamingConvention, errInvalidValue)
+	}
+	if opts.NearDuplicateKeyDistance < 0 {
+		return nil, fmt.Errorf("zaplint: Options.NearDuplicateKeyDistance=%d: %w", opts.NearDuplicateKeyDistance, errInvalidValue)
+	}
+
+	var regexps []*regexp.Regexp
+	for _, pattern := range opts.ExcludeFiles {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("zaplint: Options.ExcludeFiles=%s: %w", pattern, err)
+		}
+		regexps = append(regexps, re)
+	}
+	return regexps, nil
+}
+
+// flags returns the flags of the analyzer, which set opts and call changed
+// after each flag.
+func flags(opts *Options, changed func()) flag.FlagSet {
 	fset := flag.NewFlagSet("zaplint", flag.ContinueOnError)
+	if opts.explicit == nil {
+		opts.explicit = make(map[string]bool)
+	}
+	given := func(name string) {
+		opts.explicit[name] = true
+		changed()
+	}
 
 	boolVar := func(value *bool, name, usage string) {
 		fset.Func(name, usage, func(s string) error {
@@ -76,6 +218,7 @@ func flags(opts *Options) flag.FlagSet {
 				return err
 			}
 			*value = v
+			given(name)
 			return nil
 		})
 	}
@@ -83,13 +226,32 @@ func flags(opts *Options) flag.FlagSet {
 	strVar := func(value *string, name, usage string) {
 		fset.Func(name, usage, func(s string) error {
 			*value = s
+			given(name)
+			return nil
+		})
+	}
+
+	intVar := func(value *int, name, usage string) {
+		fset.Func(name, usage, func(s string) error {
+			v, err := strconv.Atoi(s)
+			if err != nil {
+				return err
+			}
+			*value = v
+			given(name)
 			return nil
 		})
 	}
 
 	strSliceVar := func(value *[]string, name, usage string) {
 		fset.Func(name, usage, func(s string) error {
-			*value = strings.Split(s, ",")
+			// An empty value clears the list rather than adding a pattern
+			// that matches everything.
+			*value = nil
+			if s != "" {
+				*value = strings.Split(s, ",")
+			}
+			given(name)
 			return nil
 		})
 	}
@@ -97,19 +259,78 @@ func flags(opts *Options) flag.FlagSet {
 	boolVar(&opts.CapitalizedMessage, "capitalized-message", "enforce capitalized message")
 	boolVar(&opts.ReplaceAny, "replace-any", "enforce replacing zap.Any with the appropriate type")
 	strVar(&opts.KeyNamingConvention, "key-naming-convention", "enforce a single key naming convention (snake|kebab|camel|pascal)")
+	boolVar(&opts.KeyTypeConsistency, "key-type-consistency", "enforce logging each key with the same type across packages")
+	strVar(&opts.KeyRegistry, "key-registry", "path to a key registry file listing the keys that may be logged")
+	boolVar(&opts.NearDuplicateKeys, "near-duplicate-keys", "report keys spelled like a more common key, across packages")
+	intVar(&opts.NearDuplicateKeyDistance, "near-duplicate-key-distance", "the edit distance up to which keys are near-duplicates (default: only keys of the same words)")
 	strSliceVar(&opts.ExcludeFiles, "exclude-files", "exclude files matching the given patterns")
+	boolVar(&opts.ReportUnusedDirectives, "report-unused-directives", "report suppression directives that do not suppress any diagnostic")
+	strVar(&opts.Config, "config", "path to a configuration file (default: search for .zaplint.yaml upward from each package)")
 	return *fset
 }
 
-func run(pass *analysis.Pass, opts *Options, regexps []*regexp.Regexp) {
+func run(pass *analysis.Pass, opts *Options, regexps []*regexp.Regexp, reg *registry) {
 	visitor := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
 	filter := []ast.Node{(*ast.CallExpr)(nil)}
 
+	var files []*ast.File
+	for _, file := range pass.Files {
+		if !shouldExclude(pass.Fset.Position(file.Pos()).Filename, regexps) {
+			files = append(files, file)
+		}
+	}
+	pass, directives := suppressReports(pass, files)
+
+	var keys *keyTypes
+	if opts.KeyTypeConsistency {
+		// Packages where the rule is disabled neither report conflicts nor
+		// record their keys for the packages that import them.
+		keys = newKeyTypes(pass, files)
+	}
+	var counts *keyCounts
+	if opts.NearDuplicateKeys {
+		counts = newKeyCounts(pass, opts.NearDuplicateKeyDistance)
+	}
 	visitor.Preorder(filter, func(node ast.Node) {
 		if shouldExclude(pass.Fset.Position(node.Pos()).Filename, regexps) {
 			return
 		}
 		visit(pass, opts, node)
+		if reg != nil {
+			checkKeyRegistry(pass, reg, node.(*ast.CallExpr))
+		}
+		if keys != nil {
+			keys.visit(pass, node.(*ast.CallExpr))
+		}
+		if counts != nil {
+			counts.visit(pass, node.(*ast.CallExpr))
+		}
+	})
+	// Facts are not declared when the rules were only enabled by the
+	// configuration file of a package, so they only compare its own keys.
+	exportFacts := len(pass.Analyzer.FactTypes) > 0
+	if keys != nil && exportFacts {
+		keys.export(pass)
+	}
+	if counts != nil {
+		counts.report(pass, files)
+		if exportFacts {
+			counts.export(pass)
+		}
+	}
+
+	if opts.ReportUnusedDirectives {
+		reportUnusedDirectives(pass, opts, directives)
+	}
+}
+
+// reportf reports a diagnostic of the given rule.
+func reportf(pass *analysis.Pass, rule string, rng analysis.Range, format string, args ...any) {
+	pass.Report(analysis.Diagnostic{
+		Pos:      rng.Pos(),
+		End:      rng.End(),
+		Category: rule,
+		Message:  fmt.Sprintf(format, args...),
 	})
 }
 
@@ -142,26 +363,39 @@ func visit(pass *analysis.Pass, opts *Options, node ast.Node) {
 }
 
 func checkCapitalizedMessage(pass *analysis.Pass, call *ast.CallExpr) {
-	sel, ok := call.Fun.(*ast.SelectorExpr)
-	if ok {
-		if _, ok := level[sel.Sel.Name]; ok {
-			if len(call.Args) == 0 {
-				return
-			}
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil {
+		return
+	}
 
-			msg, ok := call.Args[0].(*ast.BasicLit)
-			if !ok || msg.Kind != token.STRING {
-				return
-			}
+	index, ok := logMethods[fullName(fn)]
+	if !ok || len(call.Args) <= index {
+		return
+	}
 
-			// Trim the quotes from the message value
-			msgValue := strings.Trim(msg.Value, "\"")
+	msgValue, msg, obj, ok := constantString(pass, call.Args[index])
+	if !ok || isCapitalized(msgValue) {
+		return
+	}
 
-			if !isCapitalized(msgValue) {
-				pass.Reportf(msg.Pos(), "message '%s' should be capitalized", msgValue)
-			}
-		}
+	diag := analysis.Diagnostic{
+		Pos:      call.Args[index].Pos(),
+		End:      call.Args[index].End(),
+		Category: ruleCapitalizedMessage,
+		Message:  fmt.Sprintf("message '%s' should be capitalized", msgValue),
+		Related:  declaredAt(obj),
+	}
+	if msg == nil {
+		pass.Report(diag)
+		return
 	}
+	if edit, ok := capitalizeLiteral(msg); ok {
+		diag.SuggestedFixes = []analysis.SuggestedFix{{
+			Message:   "Capitalize the message",
+			TextEdits: []analysis.TextEdit{edit},
+		}}
+	}
+	pass.Report(diag)
 }
 
 func checkKeyNamingConvention(pass *analysis.Pass, opts *Options, call *ast.CallExpr) {
@@ -170,40 +404,183 @@ func checkKeyNamingConvention(pass *analysis.Pass, opts *Options, call *ast.Call
 		return
 	}
 
-	name := strings.TrimLeft(fn.FullName(), "vendor/")
-	if _, ok := zapFields[name]; !ok {
+	name := fullName(fn)
+	if _, ok := zapFields[name]; ok {
+		if len(call.Args) == 0 {
+			return
+		}
+		checkKey(pass, opts, call.Args[0])
 		return
 	}
 
-	if len(call.Args) == 0 {
+	if start, ok := sugaredMethods[name]; ok {
+		checkKeysAndValues(pass, opts, call, start)
+	}
+}
+
+// checkKeysAndValues checks the loosely typed key-value pairs passed to a
+// SugaredLogger method, starting at the argument with the given index.
+// Strongly typed fields may be mixed in and are skipped, as zap does.
+func checkKeysAndValues(pass *analysis.Pass, opts *Options, call *ast.CallExpr, start int) {
+	// The pairs cannot be inspected when a slice is spread into the call.
+	if call.Ellipsis.IsValid() || len(call.Args) <= start {
 		return
 	}
 
-	key, ok := call.Args[0].(*ast.BasicLit)
-	if !ok || key.Kind != token.STRING {
+	args := call.Args[start:]
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if isZapField(pass.TypesInfo.TypeOf(arg)) {
+			continue
+		}
+
+		if i == len(args)-1 {
+			reportf(pass, ruleKeyNamingConvention, arg, "odd number of arguments passed as key-value pairs")
+			return
+		}
+		// Skip over the value.
+		i++
+
+		if t := pass.TypesInfo.TypeOf(arg); !isStringType(t) {
+			reportf(pass, ruleKeyNamingConvention, arg, "key should be a string, got %s", types.TypeString(t, types.RelativeTo(pass.Pkg)))
+			continue
+		}
+
+		if pass.TypesInfo.Types[arg].Value == nil {
+			reportf(pass, ruleKeyNamingConvention, arg, "key should be a constant string")
+			continue
+		}
+
+		checkKey(pass, opts, arg)
+	}
+}
+
+// checkKey reports a key that does not follow the configured naming
+// convention.
+func checkKey(pass *analysis.Pass, opts *Options, expr ast.Expr) {
+	keyValue, key, obj, ok := constantString(pass, expr)
+	if !ok || isValidKey(keyValue, opts.KeyNamingConvention) {
 		return
 	}
 
-	// Trim the quotes from the key value
-	keyValue := strings.Trim(key.Value, "\"")
+	diag := analysis.Diagnostic{
+		Pos:      expr.Pos(),
+		End:      expr.End(),
+		Category: ruleKeyNamingConvention,
+		Message:  fmt.Sprintf("key '%s' should be in %s", keyValue, caseMap[opts.KeyNamingConvention]),
+		Related:  declaredAt(obj),
+	}
+	if key == nil {
+		pass.Report(diag)
+		return
+	}
+	// Keys without letters or digits, such as "--", have no name to
+	// convert, so they are only reported.
+	if converted := convertKey(keyValue, opts.KeyNamingConvention); converted != "" && isValidKey(converted, opts.KeyNamingConvention) {
+		diag.SuggestedFixes = []analysis.SuggestedFix{{
+			Message: fmt.Sprintf("Rename key to '%s'", converted),
+			TextEdits: []analysis.TextEdit{{
+				Pos:     key.Pos(),
+				End:     key.End(),
+				NewText: []byte(quoteLike(key.Value, converted)),
+			}},
+		}}
+	}
+	pass.Report(diag)
+}
+
+// constantString evaluates expr as a constant string. It also returns,
+// when the value is spelled out as a single string literal in expr, that
+// literal, which may be rewritten to fix the problem, and the named constant
+// that expr refers to, if any.
+//
+// Problems with the value are reported at expr, so that directives at the
+// use suppress them, with the declaration of the constant as related
+// information. Constants are not fixed, since rewriting the declaration
+// would also change the uses of the constant that have nothing to do with
+// logging.
+func constantString(pass *analysis.Pass, expr ast.Expr) (string, *ast.BasicLit, *types.Const, bool) {
+	expr = ast.Unparen(expr)
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", nil, nil, false
+	}
+	value := constant.StringVal(tv.Value)
+
+	var id *ast.Ident
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return value, e, nil, true
+	case *ast.Ident:
+		id = e
+	case *ast.SelectorExpr:
+		id = e.Sel
+	case *ast.CallExpr:
+		// Look through conversions such as string(key).
+		if len(e.Args) == 1 && pass.TypesInfo.Types[e.Fun].IsType() {
+			if value, lit, obj, ok := constantString(pass, e.Args[0]); ok {
+				return value, lit, obj, true
+			}
+		}
+	}
+	if id != nil {
+		if obj, ok := pass.TypesInfo.Uses[id].(*types.Const); ok {
+			return value, nil, obj, true
+		}
+	}
+	return value, nil, nil, true
+}
 
-	if !isValidKey(keyValue, opts.KeyNamingConvention) {
-		pass.Reportf(key.Pos(), "key '%s' should be in %s", keyValue, caseMap[opts.KeyNamingConvention])
+// declaredAt returns the position of the declaration of the constant obj as
+// related information, if obj is not nil and its position is known.
+func declaredAt(obj *types.Const) []analysis.RelatedInformation {
+	if obj == nil || !obj.Pos().IsValid() {
+		return nil
 	}
+	return []analysis.RelatedInformation{{
+		Pos:     obj.Pos(),
+		Message: fmt.Sprintf("constant %s is declared here", obj.Name()),
+	}}
 }
 
 func checkReplaceAny(pass *analysis.Pass, call *ast.CallExpr) {
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil || fullName(fn) != "go.uber.org/zap.Any" {
+		return
+	}
+
 	sel, ok := call.Fun.(*ast.SelectorExpr)
-	if ok && sel.Sel.Name == "Any" {
-		if len(call.Args) < 2 {
-			return
-		}
-		argType := pass.TypesInfo.TypeOf(call.Args[1])
-		t := getType(argType)
-		if t != "" {
-			pass.Reportf(sel.Pos(), "replace zap.Any with zap.%s", t)
-		}
+	if !ok || len(call.Args) < 2 {
+		return
+	}
+
+	argType := pass.TypesInfo.TypeOf(call.Args[1])
+	t := getType(argType)
+	if t == "" {
+		return
 	}
+
+	diag := analysis.Diagnostic{
+		Pos:      sel.Pos(),
+		End:      call.End(),
+		Category: ruleReplaceAny,
+		Message:  fmt.Sprintf("replace zap.Any with zap.%s", t),
+	}
+	// Arrays are reported but not fixed: the typed constructors take
+	// slices, and an array literal cannot be sliced in place.
+	if _, ok := argType.Underlying().(*types.Array); !ok {
+		// Only the selector name is rewritten so that aliased imports
+		// of zap are preserved.
+		diag.SuggestedFixes = []analysis.SuggestedFix{{
+			Message: fmt.Sprintf("Replace zap.Any with zap.%s", t),
+			TextEdits: []analysis.TextEdit{{
+				Pos:     sel.Sel.Pos(),
+				End:     sel.Sel.End(),
+				NewText: []byte(t),
+			}},
+		}}
+	}
+	pass.Report(diag)
 }
 
 var caseMap = map[string]string{
@@ -270,11 +647,147 @@ func isPascalCase(key string) bool {
 	return true
 }
 
+// convertKey rewrites key in the given naming convention.
+func convertKey(key, convention string) string {
+	words := splitWords(key)
+	switch convention {
+	case SnakeCase:
+		return strings.Join(words, "_")
+	case KebabCase:
+		return strings.Join(words, "-")
+	case CamelCase, PascalCase:
+		var b strings.Builder
+		for i, word := range words {
+			if i == 0 && convention == CamelCase {
+				b.WriteString(word)
+				continue
+			}
+			r, size := utf8.DecodeRuneInString(word)
+			b.WriteRune(unicode.ToUpper(r))
+			b.WriteString(word[size:])
+		}
+		return b.String()
+	default:
+		return key
+	}
+}
+
+// splitWords splits key into lower-cased words. Any character other than a
+// letter or digit separates words, as does a switch from a lower-case letter
+// or digit to an upper-case one. A run of upper-case letters is kept together
+// as an acronym, so "HTTPResponse" yields "http" and "response".
+func splitWords(key string) []string {
+	var (
+		words []string
+		word  []rune
+	)
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = nil
+		}
+	}
+
+	runes := []rune(key)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(word) > 0 {
+			prev := runes[i-1]
+			switch {
+			case unicode.IsLower(prev), unicode.IsDigit(prev):
+				flush()
+			case unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+				// The last upper-case letter of an acronym starts the next word.
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+	return words
+}
+
+// quoteLike quotes s using the same quoting style as the string literal lit.
+func quoteLike(lit, s string) string {
+	if strings.HasPrefix(lit, "`") && !strings.Contains(s, "`") {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
 func isCapitalized(s string) bool {
-	if len(s) == 0 {
-		return false
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsUpper(r) || unicode.IsTitle(r)
+}
+
+// capitalizeLiteral returns an edit that title-cases the first character of
+// the string literal lit. Only the source text of that character is
+// rewritten, so the rest of the literal, including any escape sequences, is
+// left untouched. If the first character is itself an escape sequence, the
+// replacement is written using the same kind of escape.
+func capitalizeLiteral(lit *ast.BasicLit) (analysis.TextEdit, bool) {
+	body := lit.Value[1:]
+
+	var (
+		size    int
+		newText string
+	)
+	if lit.Value[0] == '"' && strings.HasPrefix(body, `\`) {
+		r, _, tail, err := strconv.UnquoteChar(body, '"')
+		if err != nil {
+			return analysis.TextEdit{}, false
+		}
+		size = len(body) - len(tail)
+		upper := unicode.ToTitle(r)
+		if upper == r {
+			return analysis.TextEdit{}, false
+		}
+
+		escape := body[:size]
+		verb := "x"
+		if strings.ContainsAny(escape[2:], "ABCDEF") {
+			verb = "X"
+		}
+		switch escape[1] {
+		case 'u':
+			if upper > 0xFFFF {
+				return analysis.TextEdit{}, false
+			}
+			newText = fmt.Sprintf(`\u%04`+verb, upper)
+		case 'U':
+			newText = fmt.Sprintf(`\U%08`+verb, upper)
+		case 'x':
+			// Byte escapes may be part of a multi-byte sequence, so only
+			// ASCII is rewritten.
+			if r >= utf8.RuneSelf {
+				return analysis.TextEdit{}, false
+			}
+			newText = fmt.Sprintf(`\x%02`+verb, upper)
+		default:
+			if r >= utf8.RuneSelf {
+				return analysis.TextEdit{}, false
+			}
+			newText = fmt.Sprintf(`\%03o`, upper)
+		}
+	} else {
+		var r rune
+		r, size = utf8.DecodeRuneInString(body)
+		upper := unicode.ToTitle(r)
+		if r == utf8.RuneError || upper == r {
+			return analysis.TextEdit{}, false
+		}
+		newText = string(upper)
 	}
-	return s[0] >= 'A' && s[0] <= 'Z'
+
+	start := lit.Pos() + 1
+	return analysis.TextEdit{
+		Pos:     start,
+		End:     start + token.Pos(size),
+		NewText: []byte(newText),
+	}, true
 }
 
 var zapFields = map[string]struct{}{
@@ -348,6 +861,21 @@ var zapFields = map[string]struct{}{
 	"go.uber.org/zap.Errors":       {},
 }
 
+// sugaredMethods maps the SugaredLogger methods that accept loosely typed
+// key-value pairs to the index of the first pair.
+var sugaredMethods = map[string]int{
+	"(*go.uber.org/zap.SugaredLogger).With":     0,
+	"(*go.uber.org/zap.SugaredLogger).WithLazy": 0,
+	"(*go.uber.org/zap.SugaredLogger).Logw":     2,
+	"(*go.uber.org/zap.SugaredLogger).Debugw":   1,
+	"(*go.uber.org/zap.SugaredLogger).Infow":    1,
+	"(*go.uber.org/zap.SugaredLogger).Warnw":    1,
+	"(*go.uber.org/zap.SugaredLogger).Errorw":   1,
+	"(*go.uber.org/zap.SugaredLogger).DPanicw":  1,
+	"(*go.uber.org/zap.SugaredLogger).Panicw":   1,
+	"(*go.uber.org/zap.SugaredLogger).Fatalw":   1,
+}
+
 var level = map[string]struct{}{
 	"Debug":  {},
 	"Info":   {},
@@ -358,6 +886,61 @@ var level = map[string]struct{}{
 	"Fatal":  {},
 }
 
+// logMethods maps the Logger and SugaredLogger methods that take a log
+// message to the index of the message argument.
+var logMethods = func() map[string]int {
+	methods := map[string]int{
+		"(*go.uber.org/zap.Logger).Log":          1,
+		"(*go.uber.org/zap.Logger).Check":        1,
+		"(*go.uber.org/zap.SugaredLogger).Log":   1,
+		"(*go.uber.org/zap.SugaredLogger).Logf":  1,
+		"(*go.uber.org/zap.SugaredLogger).Logw":  1,
+		"(*go.uber.org/zap.SugaredLogger).Logln": 1,
+	}
+	for lvl := range level {
+		methods["(*go.uber.org/zap.Logger)."+lvl] = 0
+		for _, suffix := range []string{"", "f", "w", "ln"} {
+			methods["(*go.uber.org/zap.SugaredLogger)."+lvl+suffix] = 0
+		}
+	}
+	return methods
+}()
+
+// fullName returns the fully qualified name of fn, with any vendor directory
+// removed from its package path.
+func fullName(fn *types.Func) string {
+	name := fn.FullName()
+	if fn.Pkg() == nil {
+		return name
+	}
+	path := fn.Pkg().Path()
+	return strings.Replace(name, path, trimVendor(path), 1)
+}
+
+func trimVendor(path string) string {
+	if i := strings.LastIndex(path, "/vendor/"); i >= 0 {
+		return path[i+len("/vendor/"):]
+	}
+	return strings.TrimPrefix(path, "vendor/")
+}
+
+// isZapField reports whether t is zap.Field.
+func isZapField(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && trimVendor(obj.Pkg().Path()) == "go.uber.org/zap/zapcore" && obj.Name() == "Field"
+}
+
+// isStringType reports whether t is the predeclared string type. Named
+// string types are rejected, matching zap's own handling of keys.
+func isStringType(t types.Type) bool {
+	basic, ok := t.(*types.Basic)
+	return ok && (basic.Kind() == types.String || basic.Kind() == types.UntypedString)
+}
+
 func getType(t types.Type) string {
 	switch t.String() {
 	case "bool":
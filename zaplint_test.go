@@ -2,6 +2,8 @@ package zaplint_test
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/rleungx/zaplint"
@@ -21,7 +23,7 @@ func TestKeyNamingConvention(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			opts := &zaplint.Options{KeyNamingConvention: convention}
 			analyzer := zaplint.New(opts)
-			analysistest.Run(t, analysistest.TestData(), analyzer, fmt.Sprintf("key_naming_convention/%s", name))
+			analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), analyzer, fmt.Sprintf("key_naming_convention/%s", name))
 		})
 	}
 }
@@ -30,7 +32,7 @@ func TestCapitalizedMessage(t *testing.T) {
 	t.Parallel()
 	opts := &zaplint.Options{CapitalizedMessage: true}
 	analyzer := zaplint.New(opts)
-	analysistest.Run(t, analysistest.TestData(), analyzer, "capitalized_message")
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), analyzer, "capitalized_message")
 }
 
 func TestExcludeFiles(t *testing.T) {
@@ -54,5 +56,194 @@ func TestReplaceAny(t *testing.T) {
 	t.Parallel()
 	opts := &zaplint.Options{ReplaceAny: true}
 	analyzer := zaplint.New(opts)
-	analysistest.Run(t, analysistest.TestData(), analyzer, "replace_any")
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), analyzer, "replace_any")
+}
+
+func TestSugaredLogger(t *testing.T) {
+	t.Parallel()
+	opts := &zaplint.Options{KeyNamingConvention: zaplint.SnakeCase}
+	analyzer := zaplint.New(opts)
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), analyzer, "sugared_logger")
+}
+
+func TestConstantFolding(t *testing.T) {
+	t.Parallel()
+	opts := &zaplint.Options{
+		CapitalizedMessage:  true,
+		KeyNamingConvention: zaplint.SnakeCase,
+	}
+	analyzer := zaplint.New(opts)
+	results := analysistest.Run(t, analysistest.TestData(), analyzer, "constant_folding")
+
+	// Constants are reported where they are used, with their declaration
+	// as related information.
+	for _, diag := range results[0].Diagnostics {
+		if diag.Message != "key 'userID' should be in snake_case" || len(diag.Related) == 0 {
+			continue
+		}
+		related := diag.Related[0]
+		posn := results[0].Pass.Fset.Position(related.Pos)
+		if got, want := fmt.Sprintf("%s:%d: %s", filepath.Base(posn.Filename), posn.Line, related.Message), "constant.go:11: constant keyUserID is declared here"; got != want {
+			t.Errorf("got related information %s, want %s", got, want)
+		}
+		return
+	}
+	t.Error("no diagnostic with related information")
+}
+
+func TestConfigFile(t *testing.T) {
+	t.Parallel()
+	analyzer := zaplint.New(nil)
+	analysistest.Run(t, analysistest.TestData(), analyzer, "config_file")
+
+	opts := &zaplint.Options{Config: filepath.Join(analysistest.TestData(), "src", "config_file", ".zaplint.yaml")}
+	analyzer = zaplint.New(opts)
+	analysistest.Run(t, analysistest.TestData(), analyzer, "config_file")
+}
+
+func TestConfigCascade(t *testing.T) {
+	t.Parallel()
+	analyzer := zaplint.New(nil)
+	analysistest.Run(t, analysistest.TestData(), analyzer, "config_cascade/...")
+}
+
+func TestSuppress(t *testing.T) {
+	t.Parallel()
+	opts := &zaplint.Options{
+		CapitalizedMessage:  true,
+		ReplaceAny:          true,
+		KeyNamingConvention: zaplint.SnakeCase,
+	}
+	analyzer := zaplint.New(opts)
+	analysistest.Run(t, analysistest.TestData(), analyzer, "suppress")
+}
+
+func TestReportUnusedDirectives(t *testing.T) {
+	t.Parallel()
+	opts := &zaplint.Options{
+		CapitalizedMessage:     true,
+		KeyNamingConvention:    zaplint.SnakeCase,
+		ReportUnusedDirectives: true,
+	}
+	analyzer := zaplint.New(opts)
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), analyzer, "suppress_unused")
+}
+
+func TestInventory(t *testing.T) {
+	t.Parallel()
+	results := analysistest.Run(t, analysistest.TestData(), zaplint.Inventory, "inventory")
+	calls := results[0].Result.([]*zaplint.LogCall)
+
+	var got []string
+	for _, call := range calls {
+		s := fmt.Sprintf("%s %s.%s %s", call.Function, call.Kind, call.Method, call.Level)
+		if call.ConstantMessage {
+			s += fmt.Sprintf(" %q", call.Message)
+		}
+		for _, f := range call.Fields {
+			s += fmt.Sprintf(" %s=%s(%s)", f.Key, f.Constructor, f.Type)
+			if f.Encoding != f.Constructor {
+				s += "->" + f.Encoding
+			}
+		}
+		got = append(got, s)
+	}
+	want := []string{
+		`(*Server).Start Logger.Info info "Server started" addr=zap.String(string) timeout=zap.Duration(time.Duration)`,
+		`(*Server).Start Logger.Error error "Start failed" error=zap.Error(error) attempt=zap.Any(int)->zap.Int`,
+		`(*Server).Start Logger.Log warn "Slow start" elapsed_ms=zap.Int64(int64)`,
+		`Server.Stop SugaredLogger.Infow info "Server stopped" reason=(string)->zap.String graceful=zap.Bool(bool) code=(int)->zap.Int`,
+		`Server.Stop SugaredLogger.Warnf warn "Stopping %s"`,
+		`Server.Stop Logger.Debug debug`,
+		`helper Logger.Log  "Dynamic level"`,
+		`helper Logger.Check debug "Checked"`,
+		`helper SugaredLogger.Errorw error "In closure" count=(int)->zap.Int`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d calls, want %d:\n%s", len(got), len(want), strings.Join(got, "\n"))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("call %d:\ngot  %s\nwant %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestKeyTypeConsistency(t *testing.T) {
+	t.Parallel()
+	opts := &zaplint.Options{KeyTypeConsistency: true}
+	analyzer := zaplint.New(opts)
+	results := analysistest.Run(t, analysistest.TestData(), analyzer, "key_type_consistency", "key_type_consistency/siblings", "key_type_consistency/diamond")
+
+	// The first diagnostic points to the use of the key in the dependency.
+	result := results[0]
+	if len(result.Diagnostics) == 0 {
+		t.Fatal("got no diagnostics")
+	}
+	related := result.Diagnostics[0].Related
+	if len(related) != 1 {
+		t.Fatalf("got %d related locations, want 1", len(related))
+	}
+	posn := result.Pass.Fset.Position(related[0].Pos)
+	if got, want := fmt.Sprintf("%s:%d:%d", filepath.Base(posn.Filename), posn.Line, posn.Column), "users.go:6:43"; got != want {
+		t.Errorf("got related location %s, want %s", got, want)
+	}
+	if got, want := related[0].Message, "key 'user_id' is logged as zap.String here"; got != want {
+		t.Errorf("got related message %q, want %q", got, want)
+	}
+}
+
+func TestFactTypes(t *testing.T) {
+	t.Parallel()
+	analyzer := zaplint.New(&zaplint.Options{CapitalizedMessage: true})
+	if len(analyzer.FactTypes) != 0 {
+		t.Errorf("got fact types %v without the rules that need them", analyzer.FactTypes)
+	}
+	if err := analyzer.Flags.Set("key-type-consistency", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if len(analyzer.FactTypes) == 0 {
+		t.Error("got no fact types with key-type-consistency")
+	}
+}
+
+func TestKeyRegistry(t *testing.T) {
+	t.Parallel()
+	opts := &zaplint.Options{KeyRegistry: filepath.Join(analysistest.TestData(), "src", "key_registry", "registry.yaml")}
+	analyzer := zaplint.New(opts)
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), analyzer, "key_registry")
+}
+
+func TestNearDuplicateKeys(t *testing.T) {
+	t.Parallel()
+	opts := &zaplint.Options{NearDuplicateKeys: true}
+	analyzer := zaplint.New(opts)
+	results := analysistest.Run(t, analysistest.TestData(), analyzer, "near_duplicate_keys/words")
+
+	// The diagnostics point to the first use of the most common spelling.
+	for _, diag := range results[0].Diagnostics {
+		if len(diag.Related) != 1 {
+			t.Fatalf("got %d related locations, want 1", len(diag.Related))
+		}
+	}
+	related := results[0].Diagnostics[0].Related[0]
+	posn := results[0].Pass.Fset.Position(related.Pos)
+	if got, want := fmt.Sprintf("%s:%d:%d", filepath.Base(posn.Filename), posn.Line, posn.Column), "users.go:6:43"; got != want {
+		t.Errorf("got related location %s, want %s", got, want)
+	}
+	if got, want := related.Message, "key 'user_id' is logged here"; got != want {
+		t.Errorf("got related message %q, want %q", got, want)
+	}
+
+	opts = &zaplint.Options{NearDuplicateKeys: true, NearDuplicateKeyDistance: 1}
+	analyzer = zaplint.New(opts)
+	analysistest.Run(t, analysistest.TestData(), analyzer, "near_duplicate_keys/distance")
+
+	// Keys logged by sibling dependencies are reported at the import.
+	opts = &zaplint.Options{NearDuplicateKeys: true}
+	analyzer = zaplint.New(opts)
+	results = analysistest.Run(t, analysistest.TestData(), analyzer, "near_duplicate_keys/siblings", "near_duplicate_keys/diamond")
+	if diags := results[0].Diagnostics; len(diags) != 1 || len(diags[0].Related) != 1 {
+		t.Fatalf("got diagnostics %v, want 1 with a related location", diags)
+	}
 }